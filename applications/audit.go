@@ -0,0 +1,79 @@
+package applications
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/MrAndreID/goechoms/applications/databases/models"
+	"github.com/MrAndreID/goechoms/applications/types"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	AuditActionCreate string = "create"
+	AuditActionEdit   string = "edit"
+	AuditActionDelete string = "delete"
+)
+
+// RecordAudit must be called with the transaction of the change, so the audit log is only kept when the change is committed.
+func (app *Application) RecordAudit(tx *gorm.DB, c echo.Context, action string, resourceType string, resourceID string, before interface{}, after interface{}) error {
+	auditUUID, err := uuid.NewRandom()
+
+	if err != nil {
+		return err
+	}
+
+	auditLog := models.AuditLog{
+		ID:           auditUUID.String(),
+		CreatedAt:    time.Now().In(app.TimeLocation),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		ActorType:    "ip",
+		ActorID:      c.RealIP(),
+		ActorName:    c.RealIP(),
+	}
+
+	if identity, ok := c.Get("Identity").(*types.Identity); ok {
+		auditLog.ActorType = identity.Type
+		auditLog.ActorID = identity.Subject
+		auditLog.ActorName = identity.Name
+	}
+
+	if requestID, ok := c.Get("RequestID").(string); ok {
+		auditLog.RequestID = requestID
+	}
+
+	if routeName, ok := c.Get("RouteName").(string); ok {
+		auditLog.RouteName = routeName
+	}
+
+	if auditLog.Before, err = auditSnapshot(before); err != nil {
+		return err
+	}
+
+	if auditLog.After, err = auditSnapshot(after); err != nil {
+		return err
+	}
+
+	return tx.Create(&auditLog).Error
+}
+
+func auditSnapshot(value interface{}) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	snapshot, err := json.Marshal(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := string(snapshot)
+
+	return &result, nil
+}
@@ -22,7 +22,7 @@ func (app *Application) BindRequest(c echo.Context, i request) error {
 	binder := new(echo.DefaultBinder)
 
 	if err := binder.BindBody(c, i); err != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "01",
 			"error": err.Error(),
 		}).Error("failed to default bind body")
@@ -31,7 +31,7 @@ func (app *Application) BindRequest(c echo.Context, i request) error {
 	}
 
 	if err := binder.BindQueryParams(c, i); err != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "02",
 			"error": err.Error(),
 		}).Error("failed to default bind query param")
@@ -40,7 +40,7 @@ func (app *Application) BindRequest(c echo.Context, i request) error {
 	}
 
 	if err := binder.BindPathParams(c, i); err != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "03",
 			"error": err.Error(),
 		}).Error("failed to default bind path param")
@@ -49,7 +49,7 @@ func (app *Application) BindRequest(c echo.Context, i request) error {
 	}
 
 	if err := binder.BindHeaders(c, i); err != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "04",
 			"error": err.Error(),
 		}).Error("failed to default bind header")
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+
+	"github.com/MrAndreID/goechoms/applications"
+	"github.com/MrAndreID/goechoms/applications/databases"
+	"github.com/MrAndreID/goechoms/applications/databases/models"
+	"github.com/MrAndreID/goechoms/applications/encryption"
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+// The blind index is computed again from each address, so rows with a missing or stale hash are reported too.
+func main() {
+	var tag string = "Applications.Databases.Duplicates.Main.Main."
+
+	actionFlag := flag.String("action", "report", "For Action (report or index)")
+	batchFlag := flag.Int("batch", 100, "For Batch Size")
+
+	flag.Parse()
+
+	cfg, err := configs.New()
+
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.Error(),
+		}).Error("failed to initiate configuration")
+
+		return
+	}
+
+	if !cfg.UseDatabase {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "02",
+			"error": "The Database is not yet used",
+		}).Error("failed to check duplicate emails")
+
+		return
+	}
+
+	app, err := applications.New(cfg)
+
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "03",
+			"error": err.Error(),
+		}).Error("failed to initiate application")
+
+		return
+	}
+
+	var (
+		emails []models.Email
+		groups map[string][]models.Email = map[string][]models.Email{}
+		stale  []models.Email
+		total  int
+	)
+
+	fmt.Println("Start Duplicate Email Report")
+
+	result := app.Database.FindInBatches(&emails, cast.ToInt(batchFlag), func(tx *gorm.DB, batch int) error {
+		for _, email := range emails {
+			hash := encryption.BlindIndex(email.Email)
+
+			if email.EmailHash != hash {
+				stale = append(stale, email)
+			}
+
+			groups[hash] = append(groups[hash], email)
+		}
+
+		total += len(emails)
+
+		return nil
+	})
+
+	if result.Error != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "04",
+			"error": result.Error.Error(),
+		}).Error("failed to get email data")
+
+		return
+	}
+
+	var duplicates [][]models.Email
+
+	for _, group := range groups {
+		if len(group) > 1 {
+			sort.Slice(group, func(i, j int) bool {
+				return group[i].CreatedAt.Before(group[j].CreatedAt)
+			})
+
+			duplicates = append(duplicates, group)
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i][0].CreatedAt.Before(duplicates[j][0].CreatedAt)
+	})
+
+	for _, group := range duplicates {
+		fmt.Printf("Duplicate: %s (%d Emails)\n", group[0].Email, len(group))
+
+		for _, email := range group {
+			fmt.Printf("  Email ID: %s, User ID: %s, Created At: %s\n", email.ID, email.UserID, email.CreatedAt.In(app.TimeLocation).Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	fmt.Printf("Checked: %d Emails, Duplicates: %d, Stale Hashes: %d\n", total, len(duplicates), len(stale))
+
+	fmt.Println("End Duplicate Email Report")
+
+	if cast.ToString(actionFlag) != "index" {
+		return
+	}
+
+	if len(duplicates) > 0 {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "05",
+			"error": "Duplicate Emails Must Be Resolved First",
+		}).Error("failed to create index")
+
+		return
+	}
+
+	fmt.Println()
+
+	fmt.Println("Start Index")
+
+	for _, email := range stale {
+		if err := app.Database.Model(&email).Select("email_hash").UpdateColumns(&models.Email{EmailHash: encryption.BlindIndex(email.Email)}).Error; err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "06",
+				"error": err.Error(),
+			}).Error("failed to update email hash")
+
+			return
+		}
+	}
+
+	fmt.Printf("Updated: %d Email Hashes\n", len(stale))
+
+	if err := databases.CreateEmailUniqueIndex(app.Database); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "07",
+			"error": err.Error(),
+		}).Error("failed to create index")
+
+		return
+	}
+
+	fmt.Println("Created: " + databases.EmailUniqueIndex + " Index")
+
+	fmt.Println("End Index")
+}
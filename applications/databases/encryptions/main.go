@@ -0,0 +1,155 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/MrAndreID/goechoms/applications"
+	"github.com/MrAndreID/goechoms/applications/databases/models"
+	"github.com/MrAndreID/goechoms/applications/encryption"
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"gorm.io/gorm"
+)
+
+// Rows are read and written again, so plaintext rows are encrypted and rows of older keys are moved to the current key.
+func main() {
+	var tag string = "Applications.Databases.Encryptions.Main.Main."
+
+	batchFlag := flag.Int("batch", 100, "For Batch Size")
+
+	flag.Parse()
+
+	cfg, err := configs.New()
+
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.Error(),
+		}).Error("failed to initiate configuration")
+
+		return
+	}
+
+	if !cfg.UseDatabase || !cfg.UseFieldEncryption {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "02",
+			"error": "The Database or Field Encryption is not yet used",
+		}).Error("failed to encrypt")
+
+		return
+	}
+
+	app, err := applications.New(cfg)
+
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "03",
+			"error": err.Error(),
+		}).Error("failed to initiate application")
+
+		return
+	}
+
+	fmt.Println("Start Encryption")
+
+	if err := PrepareEmailTable(app); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "04",
+			"error": err.Error(),
+		}).Error("failed to prepare emails table")
+
+		return
+	}
+
+	var (
+		emails    []models.Email
+		auditLogs []models.AuditLog
+		total     int
+	)
+
+	result := app.Database.Unscoped().FindInBatches(&emails, cast.ToInt(batchFlag), func(tx *gorm.DB, batch int) error {
+		for _, email := range emails {
+			err := tx.Unscoped().Model(&email).Select("email", "email_hash").UpdateColumns(&models.Email{
+				Email:     email.Email,
+				EmailHash: encryption.BlindIndex(email.Email),
+			}).Error
+
+			if err != nil {
+				return err
+			}
+		}
+
+		total += len(emails)
+
+		fmt.Printf("Encrypted: %d Emails\n", total)
+
+		return nil
+	})
+
+	if result.Error != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "05",
+			"error": result.Error.Error(),
+		}).Error("failed to encrypt emails")
+
+		return
+	}
+
+	total = 0
+
+	result = app.Database.FindInBatches(&auditLogs, cast.ToInt(batchFlag), func(tx *gorm.DB, batch int) error {
+		for _, auditLog := range auditLogs {
+			err := tx.Model(&auditLog).Select("before_snapshot", "after_snapshot").UpdateColumns(&models.AuditLog{
+				Before: auditLog.Before,
+				After:  auditLog.After,
+			}).Error
+
+			if err != nil {
+				return err
+			}
+		}
+
+		total += len(auditLogs)
+
+		fmt.Printf("Encrypted: %d Audit Logs\n", total)
+
+		return nil
+	})
+
+	if result.Error != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "06",
+			"error": result.Error.Error(),
+		}).Error("failed to encrypt audit logs")
+
+		return
+	}
+
+	fmt.Println("End Encryption")
+}
+
+// The email column is widened for the ciphertext, and the blind index column is added to tables created before it existed.
+func PrepareEmailTable(app *applications.Application) error {
+	migrator := app.Database.Migrator()
+
+	if err := migrator.AlterColumn(&models.Email{}, "Email"); err != nil {
+		return err
+	}
+
+	if !migrator.HasColumn(&models.Email{}, "EmailHash") {
+		if err := migrator.AddColumn(&models.Email{}, "EmailHash"); err != nil {
+			return err
+		}
+	}
+
+	if !migrator.HasIndex(&models.Email{}, "EmailHash") {
+		if err := migrator.CreateIndex(&models.Email{}, "EmailHash"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
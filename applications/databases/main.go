@@ -10,6 +10,11 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	EmailUniqueIndex  string = "idx_emails_email_hash_unique"
+	EmailPrimaryIndex string = "idx_emails_user_id_primary_unique"
+)
+
 type Database struct {
 	Connection string
 	Host       string
@@ -53,7 +58,7 @@ func New(database *Database) (*gorm.DB, error) {
 func (database *Database) PostgreSQL() (*gorm.DB, error) {
 	dsn := "host=" + database.Host + " user=" + database.Username + " password=" + database.Password + " dbname=" + database.Name + " port=" + database.Port + " sslmode=" + database.SSLMode + " TimeZone=" + database.Timezone
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
@@ -72,7 +77,7 @@ func (database *Database) MySQL() (*gorm.DB, error) {
 
 	dsn := database.Username + ":" + database.Password + "@tcp(" + database.Host + ":" + database.Port + ")/" + database.Name + "?charset=" + database.Charset + "&parseTime=" + database.ParseTime + "&loc=" + timezone
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{TranslateError: true})
 
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
@@ -85,3 +90,35 @@ func (database *Database) MySQL() (*gorm.DB, error) {
 
 	return db, nil
 }
+
+// Only emails that are not deleted must be unique, with a partial index on PostgreSQL and a functional index on MySQL (8.0.13 or later), since MySQL has no partial indexes.
+func CreateEmailUniqueIndex(db *gorm.DB) error {
+	if db.Migrator().HasIndex("emails", EmailUniqueIndex) {
+		return nil
+	}
+
+	switch db.Dialector.Name() {
+	case "postgres":
+		return db.Exec("CREATE UNIQUE INDEX " + EmailUniqueIndex + " ON emails (email_hash) WHERE deleted_at IS NULL").Error
+	case "mysql":
+		return db.Exec("CREATE UNIQUE INDEX " + EmailUniqueIndex + " ON emails ((IF(deleted_at IS NULL, email_hash, NULL)))").Error
+	default:
+		return errors.New("Database Connection Not Found")
+	}
+}
+
+// A user has one primary email that is not deleted, with the same kind of indexes as the unique emails.
+func CreateEmailPrimaryIndex(db *gorm.DB) error {
+	if db.Migrator().HasIndex("emails", EmailPrimaryIndex) {
+		return nil
+	}
+
+	switch db.Dialector.Name() {
+	case "postgres":
+		return db.Exec("CREATE UNIQUE INDEX " + EmailPrimaryIndex + " ON emails (user_id) WHERE is_primary AND deleted_at IS NULL").Error
+	case "mysql":
+		return db.Exec("CREATE UNIQUE INDEX " + EmailPrimaryIndex + " ON emails ((IF(is_primary AND deleted_at IS NULL, user_id, NULL)))").Error
+	default:
+		return errors.New("Database Connection Not Found")
+	}
+}
@@ -3,23 +3,35 @@ package main
 import (
 	"flag"
 	"fmt"
+	"sort"
 
 	"github.com/MrAndreID/goechoms/applications"
+	"github.com/MrAndreID/goechoms/applications/databases"
 	"github.com/MrAndreID/goechoms/applications/databases/models"
 	"github.com/MrAndreID/goechoms/configs"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cast"
+	"gorm.io/gorm"
 )
 
 var tables map[string]interface{} = map[string]interface{}{
-	"users":  &models.User{},
-	"emails": &models.Email{},
+	"users":            &models.User{},
+	"emails":           &models.Email{},
+	"service_keys":     &models.ServiceKey{},
+	"idempotency_keys": &models.IdempotencyKey{},
+	"audit_logs":       &models.AuditLog{},
+	"roles":            &models.Role{},
+	"role_permissions": &models.RolePermission{},
 }
 
 func main() {
 	var tag string = "Applications.Databases.Migrations.Main.Main."
 
+	migrateFlag := flag.String("migrate", "default", "For Migrate")
+
+	flag.Parse()
+
 	cfg, err := configs.New()
 
 	if err != nil {
@@ -51,10 +63,6 @@ func main() {
 		return
 	}
 
-	migrateFlag := flag.String("migrate", "default", "For Migrate")
-
-	flag.Parse()
-
 	if cast.ToString(migrateFlag) == "fresh" {
 		fmt.Println("Start Drop All Tables")
 
@@ -109,6 +117,13 @@ func main() {
 
 func Migrate(app *applications.Application) error {
 	for i, v := range tables {
+		// Existing tables are changed by the steps below.
+		if app.Database.Migrator().HasTable(v) {
+			fmt.Println("Skipped: " + i + " Table")
+
+			continue
+		}
+
 		fmt.Println("Migrating: " + i + " Table")
 
 		err := app.Database.Migrator().CreateTable(v)
@@ -125,5 +140,98 @@ func Migrate(app *applications.Application) error {
 		fmt.Println("Migrated: " + i + " Table")
 	}
 
+	fmt.Println("Migrating: " + databases.EmailUniqueIndex + " Index")
+
+	if err := databases.CreateEmailUniqueIndex(app.Database); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   "Applications.Databases.Migrations.Main.Migrate.02",
+			"error": err.Error(),
+		}).Error("failed to create index")
+
+		return err
+	}
+
+	fmt.Println("Migrated: " + databases.EmailUniqueIndex + " Index")
+
+	fmt.Println("Migrating: is_primary Column")
+
+	if err := MigrateEmailPrimary(app); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   "Applications.Databases.Migrations.Main.Migrate.03",
+			"error": err.Error(),
+		}).Error("failed to migrate primary emails")
+
+		return err
+	}
+
+	fmt.Println("Migrated: is_primary Column")
+
+	fmt.Println("Migrating: " + databases.EmailPrimaryIndex + " Index")
+
+	if err := databases.CreateEmailPrimaryIndex(app.Database); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   "Applications.Databases.Migrations.Main.Migrate.04",
+			"error": err.Error(),
+		}).Error("failed to create index")
+
+		return err
+	}
+
+	fmt.Println("Migrated: " + databases.EmailPrimaryIndex + " Index")
+
+	return nil
+}
+
+// The column is added to tables created before it existed, and every user is left with one primary email, the oldest primary or else the oldest email, so the primary index can be created.
+func MigrateEmailPrimary(app *applications.Application) error {
+	migrator := app.Database.Migrator()
+
+	if !migrator.HasColumn(&models.Email{}, "Primary") {
+		if err := migrator.AddColumn(&models.Email{}, "Primary"); err != nil {
+			return err
+		}
+	}
+
+	var (
+		emails []models.Email
+		groups map[string][]models.Email = map[string][]models.Email{}
+	)
+
+	result := app.Database.Select("id", "created_at", "user_id", "is_primary").FindInBatches(&emails, 100, func(tx *gorm.DB, batch int) error {
+		for _, email := range emails {
+			groups[email.UserID] = append(groups[email.UserID], email)
+		}
+
+		return nil
+	})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	for _, group := range groups {
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].CreatedAt.Equal(group[j].CreatedAt) {
+				return group[i].ID < group[j].ID
+			}
+
+			return group[i].CreatedAt.Before(group[j].CreatedAt)
+		})
+
+		primaryID := group[0].ID
+
+		for _, email := range group {
+			if email.Primary {
+				primaryID = email.ID
+
+				break
+			}
+		}
+
+		if err := app.SetPrimaryEmail(app.Database, group, primaryID); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
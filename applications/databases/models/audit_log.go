@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+type AuditLog struct {
+	ID           string    `gorm:"primaryKey;Column:id;type:varchar(45)" json:"id"`
+	CreatedAt    time.Time `gorm:"Column:created_at;type:timestamptz;not null;index" json:"createdAt"`
+	Action       string    `gorm:"Column:action;type:varchar(20);not null" json:"action"`
+	ResourceType string    `gorm:"Column:resource_type;type:varchar(45);not null" json:"resourceType"`
+	ResourceID   string    `gorm:"Column:resource_id;type:varchar(45);not null;index" json:"resourceId"`
+	ActorType    string    `gorm:"Column:actor_type;type:varchar(20);not null" json:"actorType"`
+	ActorID      string    `gorm:"Column:actor_id;type:varchar(255);not null;index" json:"actorId"`
+	ActorName    string    `gorm:"Column:actor_name;type:varchar(255);not null" json:"actorName"`
+	RequestID    string    `gorm:"Column:request_id;type:varchar(128)" json:"requestId"`
+	RouteName    string    `gorm:"Column:route_name;type:varchar(255)" json:"routeName"`
+	Before       *string   `gorm:"Column:before_snapshot;type:text;serializer:encrypted" json:"before"`
+	After        *string   `gorm:"Column:after_snapshot;type:text;serializer:encrypted" json:"after"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"time"
 
+	"github.com/MrAndreID/goechoms/applications/encryption"
+
 	"gorm.io/gorm"
 )
 
@@ -12,7 +14,15 @@ type Email struct {
 	UpdatedAt time.Time      `gorm:"Column:updated_at;type:timestamptz;not null" json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"Column:deleted_at;type:timestamptz" json:"deletedAt"`
 	UserID    string         `gorm:"Column:user_id;type:varchar(45);not null" json:"userId"`
-	Email     string         `gorm:"Column:email;type:varchar(255);not null" json:"email"`
+	Email     string         `gorm:"Column:email;type:text;not null;serializer:encrypted" json:"email"`
+	EmailHash string         `gorm:"Column:email_hash;type:varchar(64);index" json:"-"`
+	Primary   bool           `gorm:"Column:is_primary;not null;default:false" json:"primary"`
+}
+
+func (email *Email) BeforeSave(tx *gorm.DB) error {
+	email.EmailHash = encryption.BlindIndex(email.Email)
+
+	return nil
 }
 
 func (Email) TableName() string {
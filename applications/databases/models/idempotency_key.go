@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+type IdempotencyKey struct {
+	KeyHash     string    `gorm:"primaryKey;Column:key_hash;type:varchar(64)" json:"keyHash"`
+	CreatedAt   time.Time `gorm:"Column:created_at;type:timestamptz;not null" json:"createdAt"`
+	RequestHash string    `gorm:"Column:request_hash;type:varchar(64);not null" json:"requestHash"`
+	StatusCode  int       `gorm:"Column:status_code;not null;default:0" json:"statusCode"`
+	Header      string    `gorm:"Column:header;type:text" json:"header"`
+	Body        []byte    `gorm:"Column:body" json:"body"`
+	ExpiresAt   time.Time `gorm:"Column:expires_at;type:timestamptz;not null;index" json:"expiresAt"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
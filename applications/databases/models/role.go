@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Role struct {
+	ID          string           `gorm:"primaryKey;Column:id;type:varchar(45)" json:"id"`
+	CreatedAt   time.Time        `gorm:"Column:created_at;type:timestamptz;not null" json:"createdAt"`
+	UpdatedAt   time.Time        `gorm:"Column:updated_at;type:timestamptz;not null" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt   `gorm:"Column:deleted_at;type:timestamptz" json:"deletedAt"`
+	Name        string           `gorm:"Column:name;type:varchar(255);not null;uniqueIndex" json:"name"`
+	Permissions []RolePermission `gorm:"foreignKey:RoleID;references:ID" json:"permissions"`
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+type RolePermission struct {
+	ID         string    `gorm:"primaryKey;Column:id;type:varchar(45)" json:"id"`
+	CreatedAt  time.Time `gorm:"Column:created_at;type:timestamptz;not null" json:"createdAt"`
+	RoleID     string    `gorm:"Column:role_id;type:varchar(45);not null;uniqueIndex:idx_role_permissions_role_permission" json:"roleId"`
+	Permission string    `gorm:"Column:permission;type:varchar(255);not null;uniqueIndex:idx_role_permissions_role_permission" json:"permission"`
+}
+
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ServiceKey struct {
+	ID        string         `gorm:"primaryKey;Column:id;type:varchar(45)" json:"id"`
+	CreatedAt time.Time      `gorm:"Column:created_at;type:timestamptz;not null" json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"Column:updated_at;type:timestamptz;not null" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"Column:deleted_at;type:timestamptz" json:"deletedAt"`
+	Name      string         `gorm:"Column:name;type:varchar(255);not null" json:"name"`
+	KeyHash   string         `gorm:"Column:key_hash;type:varchar(64);not null;uniqueIndex" json:"-"`
+	Routes    string         `gorm:"Column:routes;type:text;not null" json:"routes"`
+	Roles     string         `gorm:"Column:roles;type:text;not null;default:''" json:"roles"`
+	ExpiresAt *time.Time     `gorm:"Column:expires_at;type:timestamptz" json:"expiresAt"`
+	Enabled   bool           `gorm:"Column:enabled;not null;default:true" json:"enabled"`
+}
+
+func (ServiceKey) TableName() string {
+	return "service_keys"
+}
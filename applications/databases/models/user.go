@@ -13,6 +13,7 @@ type User struct {
 	DeletedAt gorm.DeletedAt `gorm:"Column:deleted_at;type:timestamptz" json:"deletedAt"`
 	Name      string         `gorm:"Column:name;type:varchar(255);not null" json:"name"`
 	Emails    []Email        `gorm:"foreignKey:UserID;references:ID" json:"emails"`
+	AuditLogs []AuditLog     `gorm:"polymorphic:Resource;polymorphicValue:user" json:"auditLogs,omitempty"`
 }
 
 func (User) TableName() string {
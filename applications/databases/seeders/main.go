@@ -48,6 +48,10 @@ var seeder map[string]map[string]interface{} = map[string]map[string]interface{}
 func main() {
 	var tag string = "Applications.Databases.Seeders.Main.Main."
 
+	seedFlag := flag.String("seed", "default", "For Seed")
+
+	flag.Parse()
+
 	cfg, err := configs.New()
 
 	if err != nil {
@@ -81,10 +85,6 @@ func main() {
 
 	fmt.Println("Start Seeder")
 
-	seedFlag := flag.String("seed", "default", "For Seed")
-
-	flag.Parse()
-
 	if cast.ToString(seedFlag) == "default" {
 		fmt.Println("Start Seed")
 
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/MrAndreID/goechoms/applications"
+	"github.com/MrAndreID/goechoms/applications/databases/models"
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+)
+
+func main() {
+	var tag string = "Applications.Databases.ServiceKeys.Main.Main."
+
+	actionFlag := flag.String("action", "create", "For Action (create or disable)")
+	idFlag := flag.String("id", "", "For Service Key ID (disable)")
+	nameFlag := flag.String("name", "", "For Client Name (create)")
+	routesFlag := flag.String("routes", "*", "For Allowed Route Names, Separated by Comma (create)")
+	rolesFlag := flag.String("roles", "", "For Role Names, Separated by Comma (create)")
+	expiresFlag := flag.String("expires", "", "For Expiry in RFC3339 (create or disable)")
+
+	flag.Parse()
+
+	cfg, err := configs.New()
+
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.Error(),
+		}).Error("failed to initiate configuration")
+
+		return
+	}
+
+	if !cfg.UseDatabase {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "02",
+			"error": "The Database is not yet used",
+		}).Error("failed to manage service key")
+
+		return
+	}
+
+	app, err := applications.New(cfg)
+
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "03",
+			"error": err.Error(),
+		}).Error("failed to initiate application")
+
+		return
+	}
+
+	var expiresAt *time.Time
+
+	if cast.ToString(expiresFlag) != "" {
+		expires, err := time.Parse(time.RFC3339, cast.ToString(expiresFlag))
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "04",
+				"error": err.Error(),
+			}).Error("failed to parse expiry")
+
+			return
+		}
+
+		expiresAt = &expires
+	}
+
+	switch cast.ToString(actionFlag) {
+	case "create":
+		if cast.ToString(nameFlag) == "" {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "05",
+				"error": "The Name is required",
+			}).Error("failed to create service key")
+
+			return
+		}
+
+		keyBytes := make([]byte, 32)
+
+		if _, err := rand.Read(keyBytes); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "06",
+				"error": err.Error(),
+			}).Error("failed to generate service key")
+
+			return
+		}
+
+		keyUUID, err := uuid.NewRandom()
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "07",
+				"error": err.Error(),
+			}).Error("failed to generate uuid")
+
+			return
+		}
+
+		key := hex.EncodeToString(keyBytes)
+
+		serviceKey := models.ServiceKey{
+			ID:        keyUUID.String(),
+			CreatedAt: time.Now().In(app.TimeLocation),
+			UpdatedAt: time.Now().In(app.TimeLocation),
+			Name:      cast.ToString(nameFlag),
+			KeyHash:   applications.HashServiceKey(key),
+			Routes:    cast.ToString(routesFlag),
+			Roles:     cast.ToString(rolesFlag),
+			ExpiresAt: expiresAt,
+			Enabled:   true,
+		}
+
+		if result := app.Database.Create(&serviceKey); result.Error != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "08",
+				"error": result.Error.Error(),
+			}).Error("failed to create service key")
+
+			return
+		}
+
+		fmt.Println("ID: " + serviceKey.ID)
+		fmt.Println("Name: " + serviceKey.Name)
+		fmt.Println("Routes: " + serviceKey.Routes)
+		fmt.Println("Roles: " + serviceKey.Roles)
+		fmt.Println("Service Key: " + key)
+		fmt.Println("The Service Key is only shown once, please store it safely")
+	case "disable":
+		var serviceKey models.ServiceKey
+
+		if result := app.Database.First(&serviceKey, "id = ?", cast.ToString(idFlag)); result.Error != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "09",
+				"error": result.Error.Error(),
+			}).Error("failed to get service key")
+
+			return
+		}
+
+		updates := map[string]interface{}{
+			"updated_at": time.Now().In(app.TimeLocation),
+		}
+
+		if expiresAt != nil {
+			updates["expires_at"] = expiresAt
+		} else {
+			updates["enabled"] = false
+		}
+
+		result := app.Database.Model(&serviceKey).Updates(updates)
+
+		if result.Error != nil || result.RowsAffected == 0 {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "10",
+				"error": result.Error,
+			}).Error("failed to disable service key")
+
+			return
+		}
+
+		// The cached record would otherwise keep the key usable until SERVICE_KEY_CACHE_TTL passes.
+		if err := app.ForgetServiceKey(context.Background(), serviceKey.KeyHash); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "11",
+				"error": err.Error(),
+			}).Error("failed to delete service key from redis")
+
+			return
+		}
+
+		fmt.Println("Disabled: " + cast.ToString(idFlag))
+	default:
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "12",
+			"error": "Action Not Found",
+		}).Error("failed to manage service key")
+	}
+}
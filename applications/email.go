@@ -0,0 +1,131 @@
+package applications
+
+import (
+	"errors"
+	"time"
+
+	"github.com/MrAndreID/goechoms/applications/databases/models"
+	"github.com/MrAndreID/goechoms/applications/encryption"
+	"github.com/MrAndreID/goechoms/applications/types"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DuplicateEmailError names the email that is already used by another user.
+type DuplicateEmailError struct {
+	Email string
+}
+
+func (err *DuplicateEmailError) Error() string {
+	return "Duplicate Email: " + err.Email
+}
+
+// SyncEmails keeps the emails of the user that are still requested, matched by their blind index, so their IDs and timestamps are kept.
+func (app *Application) SyncEmails(tx *gorm.DB, user *models.User, requested []types.CreateEmailRequest) error {
+	var (
+		now       time.Time                = time.Now().In(app.TimeLocation)
+		existing  map[string]*models.Email = map[string]*models.Email{}
+		emails    []models.Email
+		primaryID string
+	)
+
+	for i := range user.Emails {
+		existing[user.Emails[i].EmailHash] = &user.Emails[i]
+	}
+
+	for _, v := range requested {
+		hash := encryption.BlindIndex(v.Email)
+
+		if email, ok := existing[hash]; ok {
+			delete(existing, hash)
+
+			if email.Email != v.Email {
+				email.Email = v.Email
+				email.UpdatedAt = now
+
+				if err := tx.Model(email).Select("email", "email_hash", "updated_at").Updates(email).Error; err != nil {
+					return duplicateEmailError(err, v.Email)
+				}
+			}
+
+			if v.Primary || (primaryID == "" && email.Primary) {
+				primaryID = email.ID
+			}
+
+			emails = append(emails, *email)
+
+			continue
+		}
+
+		emailUUID, err := uuid.NewRandom()
+
+		if err != nil {
+			return err
+		}
+
+		email := models.Email{
+			ID:        emailUUID.String(),
+			CreatedAt: now,
+			UpdatedAt: now,
+			UserID:    user.ID,
+			Email:     v.Email,
+		}
+
+		if err := tx.Create(&email).Error; err != nil {
+			return duplicateEmailError(err, v.Email)
+		}
+
+		if v.Primary {
+			primaryID = email.ID
+		}
+
+		emails = append(emails, email)
+	}
+
+	for _, email := range existing {
+		if err := tx.Delete(email).Error; err != nil {
+			return err
+		}
+	}
+
+	if primaryID == "" && len(emails) > 0 {
+		primaryID = emails[0].ID
+	}
+
+	user.Emails = emails
+
+	return app.SetPrimaryEmail(tx, user.Emails, primaryID)
+}
+
+func duplicateEmailError(err error, email string) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return &DuplicateEmailError{Email: email}
+	}
+
+	return err
+}
+
+// The emails must be all the emails of the user, the flags are cleared before the new primary is set and unchanged emails are not updated.
+func (app *Application) SetPrimaryEmail(tx *gorm.DB, emails []models.Email, primaryID string) error {
+	var now time.Time = time.Now().In(app.TimeLocation)
+
+	for _, unset := range []bool{true, false} {
+		for i := range emails {
+			primary := emails[i].ID == primaryID
+
+			if emails[i].Primary == primary || primary == unset {
+				continue
+			}
+
+			emails[i].Primary = primary
+			emails[i].UpdatedAt = now
+
+			if err := tx.Model(&emails[i]).Select("is_primary", "updated_at").Updates(&emails[i]).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
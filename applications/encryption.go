@@ -0,0 +1,24 @@
+package applications
+
+import (
+	"encoding/base64"
+
+	"github.com/MrAndreID/goechoms/applications/encryption"
+	"github.com/MrAndreID/goechoms/configs"
+)
+
+func NewFieldCipher(cfg *configs.Config) (*encryption.Cipher, error) {
+	keys, err := configs.ParseEncryptionKeys(cfg.FieldEncryptionKeys)
+
+	if err != nil {
+		return nil, err
+	}
+
+	blindIndexKey, err := base64.StdEncoding.DecodeString(cfg.FieldBlindIndexKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return encryption.New(cfg.FieldEncryptionKeyID, keys, blindIndexKey)
+}
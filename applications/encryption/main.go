@@ -0,0 +1,208 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"gorm.io/gorm/schema"
+)
+
+const prefix string = "enc:v1:"
+
+var (
+	ErrKeyNotFound       = errors.New("Encryption Key Not Found")
+	ErrInvalidCiphertext = errors.New("Invalid Ciphertext")
+)
+
+var current atomic.Pointer[Cipher]
+
+func init() {
+	schema.RegisterSerializer("encrypted", Serializer{})
+}
+
+type Cipher struct {
+	KeyID         string
+	AEADs         map[string]cipher.AEAD
+	BlindIndexKey []byte
+}
+
+// The key ID is stored with every value, so values encrypted with an older key can still be read after a rotation.
+func New(keyID string, keys map[string][]byte, blindIndexKey []byte) (*Cipher, error) {
+	aeads := make(map[string]cipher.AEAD, len(keys))
+
+	for id, key := range keys {
+		block, err := aes.NewCipher(key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := cipher.NewGCM(block)
+
+		if err != nil {
+			return nil, err
+		}
+
+		aeads[id] = aead
+	}
+
+	if _, ok := aeads[keyID]; !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	return &Cipher{
+		KeyID:         keyID,
+		AEADs:         aeads,
+		BlindIndexKey: blindIndexKey,
+	}, nil
+}
+
+func SetDefault(c *Cipher) {
+	current.Store(c)
+}
+
+func Default() *Cipher {
+	return current.Load()
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	aead := c.AEADs[c.KeyID]
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(c.KeyID))
+
+	return prefix + c.KeyID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Values without the prefix are returned as they are, so rows written before encryption was enabled can still be read.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, encoded, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+
+	if !ok {
+		return "", ErrInvalidCiphertext
+	}
+
+	aead, ok := c.AEADs[keyID]
+
+	if !ok {
+		return "", ErrKeyNotFound
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(keyID))
+
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	return string(plaintext), nil
+}
+
+// The value is trimmed and lowercased before hashing, so lookups by the blind index are case-insensitive.
+func (c *Cipher) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, c.BlindIndexKey)
+
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// BlindIndex uses an empty key while encryption is disabled, the encryption command rebuilds the index once it is enabled.
+func BlindIndex(value string) string {
+	c := Default()
+
+	if c == nil {
+		c = &Cipher{}
+	}
+
+	return c.BlindIndex(value)
+}
+
+// Serializer encrypts string or *string fields tagged with `gorm:"serializer:encrypted"`, and stores them as they are while encryption is disabled.
+type Serializer struct{}
+
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+
+	switch typed := dbValue.(type) {
+	case nil:
+		return nil
+	case string:
+		value = typed
+	case []byte:
+		value = string(typed)
+	default:
+		return errors.New("Unsupported Encrypted Field Data: " + field.Name)
+	}
+
+	if IsEncrypted(value) {
+		c := Default()
+
+		if c == nil {
+			return ErrKeyNotFound
+		}
+
+		plaintext, err := c.Decrypt(value)
+
+		if err != nil {
+			return err
+		}
+
+		value = plaintext
+	}
+
+	return field.Set(ctx, dst, value)
+}
+
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	var value string
+
+	switch typed := fieldValue.(type) {
+	case string:
+		value = typed
+	case *string:
+		if typed == nil {
+			return nil, nil
+		}
+
+		value = *typed
+	default:
+		return nil, errors.New("Unsupported Encrypted Field Value: " + field.Name)
+	}
+
+	c := Default()
+
+	if c == nil {
+		return value, nil
+	}
+
+	return c.Encrypt(value)
+}
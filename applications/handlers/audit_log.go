@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MrAndreID/goechoms/applications"
+	"github.com/MrAndreID/goechoms/applications/databases/models"
+	"github.com/MrAndreID/goechoms/applications/types"
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type AuditLogHandler struct {
+	Config      *configs.Config
+	Application *applications.Application
+}
+
+func NewAuditLogHandler(cfg *configs.Config, app *applications.Application) *AuditLogHandler {
+	return &AuditLogHandler{
+		Config:      cfg,
+		Application: app,
+	}
+}
+
+func (alh *AuditLogHandler) Index(c echo.Context) error {
+	var (
+		request   types.GetAuditLogRequest
+		tag       string = "Applications.Handlers.AuditLog.Index."
+		paginator types.PaginatorResponse
+		auditLogs []models.AuditLog
+		orderBy   map[string]string = map[string]string{
+			"createdAt": "created_at",
+			"action":    "action",
+			"actorName": "actor_name",
+		}
+		sortBy map[string]string = map[string]string{
+			"asc":  "asc",
+			"desc": "desc",
+		}
+		search      []string = []string{"route_name"}
+		page, limit int
+		err         error
+		total       int64
+	)
+
+	if err := alh.Application.BindRequest(c, &request); err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.(*echo.HTTPError).Message,
+		}).Error("invalid request data")
+
+		return c.JSON(http.StatusBadRequest, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusBadRequest),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusBadRequest), " ", "_")),
+			Data:        err.(*echo.HTTPError).Message,
+		})
+	}
+
+	countTotal := alh.Application.Database.Model(&models.AuditLog{})
+	queryBuilder := alh.Application.Database.Model(&models.AuditLog{})
+
+	if request.UserID != "" {
+		countTotal.Where("resource_type = ? AND resource_id = ?", "user", request.UserID)
+
+		queryBuilder.Where("resource_type = ? AND resource_id = ?", "user", request.UserID)
+	}
+
+	if request.Actor != "" {
+		countTotal.Where("actor_id = ? OR actor_name = ?", request.Actor, request.Actor)
+
+		queryBuilder.Where("actor_id = ? OR actor_name = ?", request.Actor, request.Actor)
+	}
+
+	if request.StartDate != "" {
+		startDate, err := time.ParseInLocation("2006-01-02 15:04:05", request.StartDate, alh.Application.TimeLocation)
+
+		if err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "02",
+				"error": err.Error(),
+			}).Error("failed to parse start date from request")
+
+			return c.JSON(http.StatusBadRequest, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusBadRequest),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusBadRequest), " ", "_")),
+			})
+		}
+
+		countTotal.Where("created_at >= ?", startDate)
+
+		queryBuilder.Where("created_at >= ?", startDate)
+	}
+
+	if request.EndDate != "" {
+		endDate, err := time.ParseInLocation("2006-01-02 15:04:05", request.EndDate, alh.Application.TimeLocation)
+
+		if err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "03",
+				"error": err.Error(),
+			}).Error("failed to parse end date from request")
+
+			return c.JSON(http.StatusBadRequest, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusBadRequest),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusBadRequest), " ", "_")),
+			})
+		}
+
+		countTotal.Where("created_at <= ?", endDate)
+
+		queryBuilder.Where("created_at <= ?", endDate)
+	}
+
+	if request.Page != "" {
+		page, err = strconv.Atoi(request.Page)
+
+		if err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "04",
+				"error": err.Error(),
+			}).Error("failed to convert from string to int for page from request")
+
+			return c.JSON(http.StatusInternalServerError, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+			})
+		}
+	}
+
+	if request.Limit != "" {
+		limit, err = strconv.Atoi(request.Limit)
+
+		if err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "05",
+				"error": err.Error(),
+			}).Error("failed to convert from string to int for limit from request")
+
+			return c.JSON(http.StatusInternalServerError, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+			})
+		}
+	}
+
+	alh.Application.DataTable(
+		c.Request().Context(),
+		queryBuilder,
+		search,
+		orderBy[request.OrderBy],
+		sortBy[request.SortBy],
+		orderBy["createdAt"],
+		sortBy["desc"],
+		page,
+		&limit,
+		request.Search,
+		false,
+	)
+
+	queryBuilder.Find(&auditLogs)
+
+	if request.DisableCalculateTotal != "true" {
+		countTotal.Count(&total)
+	}
+
+	if len(auditLogs) >= limit {
+		paginator.NextPage = true
+	}
+
+	paginator.Data = auditLogs
+	paginator.Total = total
+
+	return c.JSON(http.StatusOK, types.MainResponse{
+		Code:        fmt.Sprintf("%04d", http.StatusOK),
+		Description: "SUCCESS",
+		Data:        paginator,
+	})
+}
@@ -33,10 +33,10 @@ func (ch *CurrencyHandler) Index(c echo.Context) error {
 		tag              string = "Applications.Handlers.Currency.Index."
 	)
 
-	ch.Application.Service.Currency.Index(&currencyResponse)
+	ch.Application.Service.Currency.Index(c.Request().Context(), &currencyResponse)
 
 	if currencyResponse.Error != nil || currencyResponse.StatusCode != 200 {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":        tag + "01",
 			"error":      currencyResponse.Error,
 			"statusCode": currencyResponse.StatusCode,
@@ -53,7 +53,7 @@ func (ch *CurrencyHandler) Index(c echo.Context) error {
 	err := json.Unmarshal([]byte(cast.ToString(currencyResponse.Body)), &responseBody)
 
 	if err != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "02",
 			"error": err.Error(),
 		}).Error("failed to json unmarshal (body from currency response)")
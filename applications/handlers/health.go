@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MrAndreID/goechoms/applications"
+	"github.com/MrAndreID/goechoms/applications/types"
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+type HealthHandler struct {
+	Config      *configs.Config
+	Application *applications.Application
+}
+
+func NewHealthHandler(cfg *configs.Config, app *applications.Application) *HealthHandler {
+	return &HealthHandler{
+		Config:      cfg,
+		Application: app,
+	}
+}
+
+func (hh *HealthHandler) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, types.MainResponse{
+		Code:        fmt.Sprintf("%04d", http.StatusOK),
+		Description: "SUCCESS",
+		Data: types.HealthResponse{
+			Status:       "UP",
+			Dependencies: map[string]types.HealthCheck{},
+		},
+	})
+}
+
+func (hh *HealthHandler) Ready(c echo.Context) error {
+	var (
+		tag    string               = "Applications.Handlers.Health.Ready."
+		result types.HealthResponse = types.HealthResponse{
+			Status:       "UP",
+			Dependencies: map[string]types.HealthCheck{},
+		}
+	)
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), time.Second*time.Duration(hh.Application.ConfigHolder.Get().DefaultTimeout))
+
+	defer cancel()
+
+	if hh.Config.UseDatabase {
+		result.Dependencies["database"] = hh.check(ctx, true, func(ctx context.Context) error {
+			sqlDB, err := hh.Application.Database.DB()
+
+			if err != nil {
+				return err
+			}
+
+			return sqlDB.PingContext(ctx)
+		})
+	}
+
+	if hh.Config.UseRedis {
+		result.Dependencies["redis"] = hh.check(ctx, true, func(ctx context.Context) error {
+			return hh.Application.Redis.Ping(ctx).Err()
+		})
+	}
+
+	if hh.Config.HealthCurrencyCheck == "optional" || hh.Config.HealthCurrencyCheck == "required" {
+		result.Dependencies["currency"] = hh.check(ctx, hh.Config.HealthCurrencyCheck == "required", hh.Application.Service.Currency.Ping)
+	}
+
+	for name, dependency := range result.Dependencies {
+		if dependency.Required && dependency.Status != "UP" {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":        tag + "01",
+				"dependency": name,
+				"error":      dependency.Error,
+			}).Error("required dependency is down")
+
+			result.Status = "DOWN"
+		}
+	}
+
+	if result.Status != "UP" {
+		return c.JSON(http.StatusServiceUnavailable, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusServiceUnavailable),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusServiceUnavailable), " ", "_")),
+			Data:        result,
+		})
+	}
+
+	return c.JSON(http.StatusOK, types.MainResponse{
+		Code:        fmt.Sprintf("%04d", http.StatusOK),
+		Description: "SUCCESS",
+		Data:        result,
+	})
+}
+
+func (hh *HealthHandler) check(ctx context.Context, required bool, ping func(ctx context.Context) error) types.HealthCheck {
+	startedAt := time.Now()
+
+	err := ping(ctx)
+
+	healthCheck := types.HealthCheck{
+		Status:   "UP",
+		Required: required,
+		Latency:  time.Since(startedAt).String(),
+	}
+
+	if err != nil {
+		healthCheck.Status = "DOWN"
+		healthCheck.Error = err.Error()
+	}
+
+	return healthCheck
+}
@@ -6,13 +6,21 @@ import (
 )
 
 type Handler struct {
-	User     *UserHandler
-	Currency *CurrencyHandler
+	User       *UserHandler
+	UserEmail  *UserEmailHandler
+	Currency   *CurrencyHandler
+	Health     *HealthHandler
+	AuditLog   *AuditLogHandler
+	Permission *PermissionHandler
 }
 
 func New(cfg *configs.Config, app *applications.Application) *Handler {
 	return &Handler{
-		User:     NewUserHandler(cfg, app),
-		Currency: NewCurrencyHandler(cfg, app),
+		User:       NewUserHandler(cfg, app),
+		UserEmail:  NewUserEmailHandler(cfg, app),
+		Currency:   NewCurrencyHandler(cfg, app),
+		Health:     NewHealthHandler(cfg, app),
+		AuditLog:   NewAuditLogHandler(cfg, app),
+		Permission: NewPermissionHandler(cfg, app),
 	}
 }
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/MrAndreID/goechoms/applications"
+	"github.com/MrAndreID/goechoms/applications/databases/models"
+	"github.com/MrAndreID/goechoms/applications/types"
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type PermissionHandler struct {
+	Config      *configs.Config
+	Application *applications.Application
+}
+
+func NewPermissionHandler(cfg *configs.Config, app *applications.Application) *PermissionHandler {
+	return &PermissionHandler{
+		Config:      cfg,
+		Application: app,
+	}
+}
+
+// Show returns the effective permissions of the caller, or of the service key in serviceKeyId when the keys are stored in the database.
+func (ph *PermissionHandler) Show(c echo.Context) error {
+	var (
+		tag        string = "Applications.Handlers.Permission.Show."
+		request    types.GetPermissionRequest
+		serviceKey models.ServiceKey
+		cfg        *configs.Config = ph.Application.ConfigHolder.Get()
+		response   types.PermissionResponse
+	)
+
+	if err := ph.Application.BindRequest(c, &request); err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.(*echo.HTTPError).Message,
+		}).Error("invalid request data")
+
+		return c.JSON(http.StatusBadRequest, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusBadRequest),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusBadRequest), " ", "_")),
+			Data:        err.(*echo.HTTPError).Message,
+		})
+	}
+
+	identity, ok := c.Get("Identity").(*types.Identity)
+
+	if !ok {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag": tag + "02",
+		}).Error("identity not found")
+
+		return c.JSON(http.StatusUnauthorized, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusUnauthorized),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusUnauthorized), " ", "_")),
+		})
+	}
+
+	if request.ServiceKeyID != "" {
+		if cfg.ServiceKeyStore != "database" {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "03",
+				"error": "The Service Keys are not stored in the database",
+			}).Error("failed to get service key data")
+
+			return c.JSON(http.StatusNotFound, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusNotFound),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusNotFound), " ", "_")),
+			})
+		}
+
+		serviceKeyResult := ph.Application.Database.WithContext(c.Request().Context()).First(&serviceKey, "id = ?", request.ServiceKeyID)
+
+		if serviceKeyResult.Error != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "04",
+				"error": serviceKeyResult.Error.Error(),
+			}).Error("failed to get service key data")
+
+			if errors.Is(serviceKeyResult.Error, gorm.ErrRecordNotFound) {
+				return c.JSON(http.StatusNotFound, types.MainResponse{
+					Code:        fmt.Sprintf("%04d", http.StatusNotFound),
+					Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusNotFound), " ", "_")),
+				})
+			}
+
+			return c.JSON(http.StatusInternalServerError, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+			})
+		}
+
+		identity = &types.Identity{
+			Type:    "service-key",
+			Subject: serviceKey.ID,
+			Name:    serviceKey.Name,
+			Scopes:  applications.ServiceKeyRoutes(&serviceKey),
+			Roles:   applications.ServiceKeyRoles(&serviceKey),
+		}
+	}
+
+	permissions, err := ph.Application.IdentityPermissions(c.Request().Context(), identity)
+
+	if err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "05",
+			"error": err.Error(),
+		}).Error("failed to get permissions")
+
+		return c.JSON(http.StatusInternalServerError, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+		})
+	}
+
+	rules, err := ph.Application.PermissionRules(cfg)
+
+	if err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "06",
+			"error": err.Error(),
+		}).Error("failed to parse permission rules")
+
+		return c.JSON(http.StatusInternalServerError, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+		})
+	}
+
+	response.Identity = identity
+	response.Permissions = permissions
+	response.Routes = map[string]bool{}
+
+	// Only the routes with permission rules are listed, service keys are also limited to their route names.
+	for _, route := range c.Echo().Routes() {
+		if len(rules.Route(route.Name)) > 0 {
+			response.Routes[route.Name] = rules.RouteAllowed(identity, permissions, route.Name)
+		}
+	}
+
+	return c.JSON(http.StatusOK, types.MainResponse{
+		Code:        fmt.Sprintf("%04d", http.StatusOK),
+		Description: "SUCCESS",
+		Data:        response,
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -9,12 +10,14 @@ import (
 
 	"github.com/MrAndreID/goechoms/applications"
 	"github.com/MrAndreID/goechoms/applications/databases/models"
+	"github.com/MrAndreID/goechoms/applications/encryption"
 	"github.com/MrAndreID/goechoms/applications/types"
 	"github.com/MrAndreID/goechoms/configs"
 	"github.com/google/uuid"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 type UserHandler struct {
@@ -52,7 +55,7 @@ func (uh *UserHandler) Index(c echo.Context) error {
 	)
 
 	if err := uh.Application.BindRequest(c, &request); err != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "01",
 			"error": err.(*echo.HTTPError).Message,
 		}).Error("invalid request data")
@@ -73,11 +76,20 @@ func (uh *UserHandler) Index(c echo.Context) error {
 		queryBuilder.Where("id = ?", request.ID)
 	}
 
+	// Emails are encrypted, so they are matched by their blind index.
+	if request.Email != "" {
+		emailQuery := uh.Application.Database.Model(&models.Email{}).Select("user_id").Where("email_hash = ?", encryption.BlindIndex(request.Email))
+
+		countTotal.Where("id IN (?)", emailQuery)
+
+		queryBuilder.Where("id IN (?)", emailQuery)
+	}
+
 	if request.Page != "" {
 		page, err = strconv.Atoi(request.Page)
 
 		if err != nil {
-			logrus.WithFields(logrus.Fields{
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 				"tag":   tag + "02",
 				"error": err.Error(),
 			}).Error("failed to convert from string to int for page from request")
@@ -93,7 +105,7 @@ func (uh *UserHandler) Index(c echo.Context) error {
 		limit, err = strconv.Atoi(request.Limit)
 
 		if err != nil {
-			logrus.WithFields(logrus.Fields{
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 				"tag":   tag + "03",
 				"error": err.Error(),
 			}).Error("failed to convert from string to int for limit from request")
@@ -139,6 +151,124 @@ func (uh *UserHandler) Index(c echo.Context) error {
 	})
 }
 
+func (uh *UserHandler) Show(c echo.Context) error {
+	var (
+		tag     string = "Applications.Handlers.User.Show."
+		request types.ShowUserRequest
+		user    models.User
+	)
+
+	if err := uh.Application.BindRequest(c, &request); err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.(*echo.HTTPError).Message,
+		}).Error("invalid request data")
+
+		return c.JSON(http.StatusBadRequest, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusBadRequest),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusBadRequest), " ", "_")),
+			Data:        err.(*echo.HTTPError).Message,
+		})
+	}
+
+	var includeAuditLogs bool
+
+	for _, include := range strings.Split(request.Include, ",") {
+		if strings.TrimSpace(include) != "auditLogs" {
+			continue
+		}
+
+		// The audit logs are only included for callers that may call the audit log route.
+		identity, ok := c.Get("Identity").(*types.Identity)
+
+		var allowed bool
+
+		if ok {
+			permissions, err := uh.Application.IdentityPermissions(c.Request().Context(), identity)
+
+			if err != nil {
+				logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+					"tag":   tag + "02",
+					"error": err.Error(),
+				}).Error("failed to get permissions")
+
+				return c.JSON(http.StatusInternalServerError, types.MainResponse{
+					Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+					Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+				})
+			}
+
+			rules, err := uh.Application.PermissionRules(uh.Application.ConfigHolder.Get())
+
+			if err != nil {
+				logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+					"tag":   tag + "03",
+					"error": err.Error(),
+				}).Error("failed to parse permission rules")
+
+				return c.JSON(http.StatusInternalServerError, types.MainResponse{
+					Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+					Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+				})
+			}
+
+			allowed = rules.RouteAllowed(identity, permissions, "audit-log.index") && (identity.Type != "jwt" || identity.HasScope("audit:read"))
+		}
+
+		if !allowed {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "04",
+				"error": "Audit Logs Are Not Allowed",
+			}).Error("insufficient permissions")
+
+			return c.JSON(http.StatusForbidden, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusForbidden),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusForbidden), " ", "_")),
+				Data: map[string][]string{
+					"include": {"auditLogs"},
+				},
+			})
+		}
+
+		includeAuditLogs = true
+	}
+
+	queryBuilder := uh.Application.Database.WithContext(c.Request().Context()).Preload("Emails")
+
+	if includeAuditLogs {
+		queryBuilder = queryBuilder.Preload("AuditLogs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at desc")
+		})
+	}
+
+	userResult := queryBuilder.First(&user, "id = ?", request.ID)
+
+	if userResult.Error != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "05",
+			"error": userResult.Error.Error(),
+		}).Error("failed to get user data")
+
+		if errors.Is(userResult.Error, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusNotFound),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusNotFound), " ", "_")),
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+		})
+	}
+
+	return c.JSON(http.StatusOK, types.MainResponse{
+		Code:        fmt.Sprintf("%04d", http.StatusOK),
+		Description: "SUCCESS",
+		Data:        user,
+	})
+}
+
 func (uh *UserHandler) Create(c echo.Context) error {
 	var (
 		tag     string = "Applications.Handlers.User.Create."
@@ -147,7 +277,7 @@ func (uh *UserHandler) Create(c echo.Context) error {
 	)
 
 	if err := uh.Application.BindRequest(c, &request); err != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "01",
 			"error": err.(*echo.HTTPError).Message,
 		}).Error("invalid request data")
@@ -164,7 +294,7 @@ func (uh *UserHandler) Create(c echo.Context) error {
 	userUUID, err := uuid.NewRandom()
 
 	if err != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "02",
 			"error": err.Error(),
 		}).Error("failed to generate uuid")
@@ -185,7 +315,7 @@ func (uh *UserHandler) Create(c echo.Context) error {
 	createUser := tx.Save(&user)
 
 	if createUser.Error != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "03",
 			"error": createUser.Error.Error(),
 		}).Error("failed to create user")
@@ -199,7 +329,7 @@ func (uh *UserHandler) Create(c echo.Context) error {
 	}
 
 	if createUser.RowsAffected == 0 {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "04",
 			"error": "Failed to Create User",
 		}).Error("failed to create user")
@@ -214,8 +344,8 @@ func (uh *UserHandler) Create(c echo.Context) error {
 
 	for i := 0; i < len(request.Emails); i++ {
 		for j := i + 1; j < len(request.Emails); j++ {
-			if request.Emails[i].Email == request.Emails[j].Email {
-				logrus.WithFields(logrus.Fields{
+			if encryption.BlindIndex(request.Emails[i].Email) == encryption.BlindIndex(request.Emails[j].Email) {
+				logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 					"tag":   tag + "05",
 					"error": "Duplicate Email",
 				}).Error("duplicate email")
@@ -230,13 +360,21 @@ func (uh *UserHandler) Create(c echo.Context) error {
 		}
 	}
 
-	for _, v := range request.Emails {
+	primaryIndex := 0
+
+	for i, v := range request.Emails {
+		if v.Primary {
+			primaryIndex = i
+		}
+	}
+
+	for i, v := range request.Emails {
 		var email models.Email
 
 		emailUUID, err := uuid.NewRandom()
 
 		if err != nil {
-			logrus.WithFields(logrus.Fields{
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 				"tag":   tag + "06",
 				"error": err.Error(),
 			}).Error("failed to generate uuid")
@@ -254,17 +392,28 @@ func (uh *UserHandler) Create(c echo.Context) error {
 		email.UpdatedAt = time.Now().In(uh.Application.TimeLocation)
 		email.UserID = user.ID
 		email.Email = v.Email
+		email.Primary = i == primaryIndex
 
 		createEmail := tx.Save(&email)
 
 		if createEmail.Error != nil {
-			logrus.WithFields(logrus.Fields{
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 				"tag":   tag + "07",
 				"error": createEmail.Error.Error(),
 			}).Error("failed to create email")
 
 			tx.Rollback()
 
+			if errors.Is(createEmail.Error, gorm.ErrDuplicatedKey) {
+				return c.JSON(http.StatusConflict, types.MainResponse{
+					Code:        fmt.Sprintf("%04d", http.StatusConflict),
+					Description: "EMAIL_ALREADY_EXISTS",
+					Data: map[string]string{
+						"email": v.Email,
+					},
+				})
+			}
+
 			return c.JSON(http.StatusInternalServerError, types.MainResponse{
 				Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
 				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
@@ -272,7 +421,7 @@ func (uh *UserHandler) Create(c echo.Context) error {
 		}
 
 		if createEmail.RowsAffected == 0 {
-			logrus.WithFields(logrus.Fields{
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 				"tag":   tag + "08",
 				"error": "Failed to Create Email",
 			}).Error("failed to create email")
@@ -288,6 +437,20 @@ func (uh *UserHandler) Create(c echo.Context) error {
 		user.Emails = append(user.Emails, email)
 	}
 
+	if err := uh.Application.RecordAudit(tx, c, applications.AuditActionCreate, "user", user.ID, nil, user); err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "09",
+			"error": err.Error(),
+		}).Error("failed to record audit log")
+
+		tx.Rollback()
+
+		return c.JSON(http.StatusInternalServerError, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+		})
+	}
+
 	tx.Commit()
 
 	return c.JSON(http.StatusCreated, types.MainResponse{
@@ -302,10 +465,11 @@ func (uh *UserHandler) Edit(c echo.Context) error {
 		tag     string = "Applications.Handlers.User.Edit."
 		request types.EditUserRequest
 		user    models.User
+		before  models.User
 	)
 
 	if err := uh.Application.BindRequest(c, &request); err != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "01",
 			"error": err.(*echo.HTTPError).Message,
 		}).Error("invalid request data")
@@ -319,10 +483,10 @@ func (uh *UserHandler) Edit(c echo.Context) error {
 
 	tx := uh.Application.Database.Begin()
 
-	userResult := tx.First(&user, "id = ?", request.ID)
+	userResult := tx.Preload("Emails").First(&user, "id = ?", request.ID)
 
 	if userResult.RowsAffected == 0 {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "02",
 			"error": "Failed to Get User Data",
 		}).Error("failed to get user data")
@@ -335,6 +499,8 @@ func (uh *UserHandler) Edit(c echo.Context) error {
 		})
 	}
 
+	before = userSnapshot(user)
+
 	if request.Name != "" {
 		user.Name = request.Name
 	}
@@ -342,8 +508,8 @@ func (uh *UserHandler) Edit(c echo.Context) error {
 	if len(request.Emails) > 0 {
 		for i := 0; i < len(request.Emails); i++ {
 			for j := i + 1; j < len(request.Emails); j++ {
-				if request.Emails[i].Email == request.Emails[j].Email {
-					logrus.WithFields(logrus.Fields{
+				if encryption.BlindIndex(request.Emails[i].Email) == encryption.BlindIndex(request.Emails[j].Email) {
+					logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 						"tag":   tag + "03",
 						"error": "Duplicate Email",
 					}).Error("duplicate email")
@@ -358,113 +524,31 @@ func (uh *UserHandler) Edit(c echo.Context) error {
 			}
 		}
 
-		deleteEmail := tx.Where("user_id = ?", user.ID).Delete(&models.Email{})
-
-		if deleteEmail.Error != nil {
-			logrus.WithFields(logrus.Fields{
+		if err := uh.Application.SyncEmails(tx, &user, request.Emails); err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 				"tag":   tag + "04",
-				"error": deleteEmail.Error.Error(),
-			}).Error("failed to delete email data")
+				"error": err.Error(),
+			}).Error("failed to sync email data")
 
 			tx.Rollback()
 
-			return c.JSON(http.StatusInternalServerError, types.MainResponse{
-				Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
-				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
-			})
-		}
-
-		if deleteEmail.RowsAffected == 0 {
-			logrus.WithFields(logrus.Fields{
-				"tag":   tag + "05",
-				"error": "Failed to Delete Email Data",
-			}).Error("failed to delete email data")
+			var duplicateEmailError *applications.DuplicateEmailError
 
-			tx.Rollback()
+			if errors.As(err, &duplicateEmailError) {
+				return c.JSON(http.StatusConflict, types.MainResponse{
+					Code:        fmt.Sprintf("%04d", http.StatusConflict),
+					Description: "EMAIL_ALREADY_EXISTS",
+					Data: map[string]string{
+						"email": duplicateEmailError.Email,
+					},
+				})
+			}
 
 			return c.JSON(http.StatusInternalServerError, types.MainResponse{
 				Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
 				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
 			})
 		}
-
-		for _, v := range request.Emails {
-			var email models.Email
-
-			emailUUID, err := uuid.NewRandom()
-
-			if err != nil {
-				logrus.WithFields(logrus.Fields{
-					"tag":   tag + "06",
-					"error": err.Error(),
-				}).Error("failed to generate uuid")
-
-				tx.Rollback()
-
-				return c.JSON(http.StatusInternalServerError, types.MainResponse{
-					Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
-					Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
-				})
-			}
-
-			email.ID = emailUUID.String()
-			email.CreatedAt = time.Now().In(uh.Application.TimeLocation)
-			email.UpdatedAt = time.Now().In(uh.Application.TimeLocation)
-			email.UserID = user.ID
-			email.Email = v.Email
-
-			createEmail := tx.Save(&email)
-
-			if createEmail.Error != nil {
-				logrus.WithFields(logrus.Fields{
-					"tag":   tag + "07",
-					"error": createEmail.Error.Error(),
-				}).Error("failed to create email")
-
-				tx.Rollback()
-
-				return c.JSON(http.StatusInternalServerError, types.MainResponse{
-					Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
-					Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
-				})
-			}
-
-			if createEmail.RowsAffected == 0 {
-				logrus.WithFields(logrus.Fields{
-					"tag":   tag + "08",
-					"error": "Failed to Create Email",
-				}).Error("failed to create email")
-
-				tx.Rollback()
-
-				return c.JSON(http.StatusInternalServerError, types.MainResponse{
-					Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
-					Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
-				})
-			}
-
-			user.Emails = append(user.Emails, email)
-		}
-	} else {
-		var emails []models.Email
-
-		emailResult := tx.Find(&emails, "user_id = ?", user.ID)
-
-		if emailResult.RowsAffected == 0 {
-			logrus.WithFields(logrus.Fields{
-				"tag":   tag + "09",
-				"error": "Failed to Get Email Data",
-			}).Error("failed to get email data")
-
-			tx.Rollback()
-
-			return c.JSON(http.StatusNotFound, types.MainResponse{
-				Code:        fmt.Sprintf("%04d", http.StatusNotFound),
-				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusNotFound), " ", "_")),
-			})
-		}
-
-		user.Emails = emails
 	}
 
 	user.UpdatedAt = time.Now().In(uh.Application.TimeLocation)
@@ -472,8 +556,8 @@ func (uh *UserHandler) Edit(c echo.Context) error {
 	editUser := tx.Save(&user)
 
 	if editUser.Error != nil {
-		logrus.WithFields(logrus.Fields{
-			"tag":   tag + "10",
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "05",
 			"error": editUser.Error.Error(),
 		}).Error("failed to edit user data")
 
@@ -486,8 +570,8 @@ func (uh *UserHandler) Edit(c echo.Context) error {
 	}
 
 	if editUser.RowsAffected == 0 {
-		logrus.WithFields(logrus.Fields{
-			"tag":   tag + "11",
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "06",
 			"error": "Failed to Edit User Data",
 		}).Error("failed to edit user data")
 
@@ -499,6 +583,20 @@ func (uh *UserHandler) Edit(c echo.Context) error {
 		})
 	}
 
+	if err := uh.Application.RecordAudit(tx, c, applications.AuditActionEdit, "user", user.ID, before, user); err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "07",
+			"error": err.Error(),
+		}).Error("failed to record audit log")
+
+		tx.Rollback()
+
+		return c.JSON(http.StatusInternalServerError, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+		})
+	}
+
 	tx.Commit()
 
 	return c.JSON(http.StatusOK, types.MainResponse{
@@ -513,10 +611,11 @@ func (uh *UserHandler) Delete(c echo.Context) error {
 		tag     string = "Applications.Handlers.User.Delete."
 		request types.DeleteUserRequest
 		user    models.User
+		before  models.User
 	)
 
 	if err := uh.Application.BindRequest(c, &request); err != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "01",
 			"error": err.(*echo.HTTPError).Message,
 		}).Error("invalid request data")
@@ -530,10 +629,10 @@ func (uh *UserHandler) Delete(c echo.Context) error {
 
 	tx := uh.Application.Database.Begin()
 
-	userResult := tx.First(&user, "id = ?", request.ID)
+	userResult := tx.Preload("Emails").First(&user, "id = ?", request.ID)
 
 	if userResult.RowsAffected == 0 {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "02",
 			"error": "Failed To Get User Data",
 		}).Error("failed to get user data")
@@ -546,10 +645,12 @@ func (uh *UserHandler) Delete(c echo.Context) error {
 		})
 	}
 
+	before = user
+
 	deleteUser := tx.Delete(&user, "id = ?", request.ID)
 
 	if deleteUser.Error != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "03",
 			"error": deleteUser.Error.Error(),
 		}).Error("failed to delete user data")
@@ -563,7 +664,7 @@ func (uh *UserHandler) Delete(c echo.Context) error {
 	}
 
 	if deleteUser.RowsAffected == 0 {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "04",
 			"error": "Failed To Delete User Data",
 		}).Error("failed to delete user data")
@@ -579,7 +680,7 @@ func (uh *UserHandler) Delete(c echo.Context) error {
 	deleteEmail := tx.Where("user_id = ?", request.ID).Delete(&models.Email{})
 
 	if deleteEmail.Error != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "05",
 			"error": deleteEmail.Error.Error(),
 		}).Error("failed to delete email data")
@@ -592,11 +693,11 @@ func (uh *UserHandler) Delete(c echo.Context) error {
 		})
 	}
 
-	if deleteEmail.RowsAffected == 0 {
-		logrus.WithFields(logrus.Fields{
+	if err := uh.Application.RecordAudit(tx, c, applications.AuditActionDelete, "user", before.ID, before, nil); err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag":   tag + "06",
-			"error": "Failed To Delete Email Data",
-		}).Error("failed to delete email data")
+			"error": err.Error(),
+		}).Error("failed to record audit log")
 
 		tx.Rollback()
 
@@ -613,3 +714,10 @@ func (uh *UserHandler) Delete(c echo.Context) error {
 		Description: "SUCCESS",
 	})
 }
+
+// The emails are copied, since they are changed in place while the user is edited.
+func userSnapshot(user models.User) models.User {
+	user.Emails = append([]models.Email{}, user.Emails...)
+
+	return user
+}
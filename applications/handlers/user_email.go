@@ -0,0 +1,424 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MrAndreID/goechoms/applications"
+	"github.com/MrAndreID/goechoms/applications/databases/models"
+	"github.com/MrAndreID/goechoms/applications/encryption"
+	"github.com/MrAndreID/goechoms/applications/types"
+	"github.com/MrAndreID/goechoms/configs"
+	"github.com/google/uuid"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type UserEmailHandler struct {
+	Config      *configs.Config
+	Application *applications.Application
+}
+
+func NewUserEmailHandler(cfg *configs.Config, app *applications.Application) *UserEmailHandler {
+	return &UserEmailHandler{
+		Config:      cfg,
+		Application: app,
+	}
+}
+
+func (ueh *UserEmailHandler) Index(c echo.Context) error {
+	var (
+		tag     string = "Applications.Handlers.UserEmail.Index."
+		request types.GetUserEmailRequest
+		user    models.User
+	)
+
+	if err := ueh.Application.BindRequest(c, &request); err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.(*echo.HTTPError).Message,
+		}).Error("invalid request data")
+
+		return c.JSON(http.StatusBadRequest, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusBadRequest),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusBadRequest), " ", "_")),
+			Data:        err.(*echo.HTTPError).Message,
+		})
+	}
+
+	userResult := ueh.Application.Database.WithContext(c.Request().Context()).Preload("Emails", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at asc")
+	}).First(&user, "id = ?", request.ID)
+
+	if userResult.Error != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "02",
+			"error": userResult.Error.Error(),
+		}).Error("failed to get user data")
+
+		if errors.Is(userResult.Error, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusNotFound),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusNotFound), " ", "_")),
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+		})
+	}
+
+	return c.JSON(http.StatusOK, types.MainResponse{
+		Code:        fmt.Sprintf("%04d", http.StatusOK),
+		Description: "SUCCESS",
+		Data:        user.Emails,
+	})
+}
+
+func (ueh *UserEmailHandler) Show(c echo.Context) error {
+	var (
+		tag     string = "Applications.Handlers.UserEmail.Show."
+		request types.ShowUserEmailRequest
+		email   models.Email
+	)
+
+	if err := ueh.Application.BindRequest(c, &request); err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.(*echo.HTTPError).Message,
+		}).Error("invalid request data")
+
+		return c.JSON(http.StatusBadRequest, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusBadRequest),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusBadRequest), " ", "_")),
+			Data:        err.(*echo.HTTPError).Message,
+		})
+	}
+
+	emailResult := ueh.Application.Database.WithContext(c.Request().Context()).First(&email, "id = ? AND user_id = ?", request.EmailID, request.ID)
+
+	if emailResult.Error != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "02",
+			"error": emailResult.Error.Error(),
+		}).Error("failed to get email data")
+
+		if errors.Is(emailResult.Error, gorm.ErrRecordNotFound) {
+			return c.JSON(http.StatusNotFound, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusNotFound),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusNotFound), " ", "_")),
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+		})
+	}
+
+	return c.JSON(http.StatusOK, types.MainResponse{
+		Code:        fmt.Sprintf("%04d", http.StatusOK),
+		Description: "SUCCESS",
+		Data:        email,
+	})
+}
+
+func (ueh *UserEmailHandler) Create(c echo.Context) error {
+	var (
+		tag     string = "Applications.Handlers.UserEmail.Create."
+		request types.CreateUserEmailRequest
+		user    models.User
+		before  models.User
+	)
+
+	if err := ueh.Application.BindRequest(c, &request); err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.(*echo.HTTPError).Message,
+		}).Error("invalid request data")
+
+		return c.JSON(http.StatusBadRequest, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusBadRequest),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusBadRequest), " ", "_")),
+			Data:        err.(*echo.HTTPError).Message,
+		})
+	}
+
+	tx := ueh.Application.Database.Begin()
+
+	userResult := tx.Preload("Emails", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at asc")
+	}).First(&user, "id = ?", request.ID)
+
+	if userResult.RowsAffected == 0 {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "02",
+			"error": "Failed To Get User Data",
+		}).Error("failed to get user data")
+
+		tx.Rollback()
+
+		return c.JSON(http.StatusNotFound, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusNotFound),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusNotFound), " ", "_")),
+		})
+	}
+
+	before = userSnapshot(user)
+
+	for _, v := range user.Emails {
+		if v.EmailHash == encryption.BlindIndex(request.Email) {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "03",
+				"error": "Duplicate Email",
+			}).Error("duplicate email")
+
+			tx.Rollback()
+
+			return c.JSON(http.StatusConflict, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusConflict),
+				Description: "EMAIL_ALREADY_EXISTS",
+				Data: map[string]string{
+					"email": request.Email,
+				},
+			})
+		}
+	}
+
+	emailUUID, err := uuid.NewRandom()
+
+	if err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "04",
+			"error": err.Error(),
+		}).Error("failed to generate uuid for email")
+
+		tx.Rollback()
+
+		return c.JSON(http.StatusInternalServerError, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+		})
+	}
+
+	email := models.Email{
+		ID:        emailUUID.String(),
+		CreatedAt: time.Now().In(ueh.Application.TimeLocation),
+		UpdatedAt: time.Now().In(ueh.Application.TimeLocation),
+		UserID:    user.ID,
+		Email:     request.Email,
+	}
+
+	createEmail := tx.Create(&email)
+
+	if createEmail.Error != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "05",
+			"error": createEmail.Error.Error(),
+		}).Error("failed to create email data")
+
+		tx.Rollback()
+
+		if errors.Is(createEmail.Error, gorm.ErrDuplicatedKey) {
+			return c.JSON(http.StatusConflict, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusConflict),
+				Description: "EMAIL_ALREADY_EXISTS",
+				Data: map[string]string{
+					"email": request.Email,
+				},
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+		})
+	}
+
+	user.Emails = append(user.Emails, email)
+
+	// The first email of a user is always primary.
+	if request.Primary || len(user.Emails) == 1 {
+		if err := ueh.Application.SetPrimaryEmail(tx, user.Emails, email.ID); err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "06",
+				"error": err.Error(),
+			}).Error("failed to set primary email")
+
+			tx.Rollback()
+
+			return c.JSON(http.StatusInternalServerError, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+			})
+		}
+
+		email = user.Emails[len(user.Emails)-1]
+	}
+
+	if err := ueh.Application.RecordAudit(tx, c, applications.AuditActionEdit, "user", user.ID, before, user); err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "07",
+			"error": err.Error(),
+		}).Error("failed to record audit log")
+
+		tx.Rollback()
+
+		return c.JSON(http.StatusInternalServerError, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+		})
+	}
+
+	tx.Commit()
+
+	return c.JSON(http.StatusCreated, types.MainResponse{
+		Code:        fmt.Sprintf("%04d", http.StatusCreated),
+		Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusCreated), " ", "_")),
+		Data:        email,
+	})
+}
+
+func (ueh *UserEmailHandler) Delete(c echo.Context) error {
+	var (
+		tag     string = "Applications.Handlers.UserEmail.Delete."
+		request types.DeleteUserEmailRequest
+		user    models.User
+		before  models.User
+		emails  []models.Email
+		email   *models.Email
+	)
+
+	if err := ueh.Application.BindRequest(c, &request); err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.(*echo.HTTPError).Message,
+		}).Error("invalid request data")
+
+		return c.JSON(http.StatusBadRequest, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusBadRequest),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusBadRequest), " ", "_")),
+			Data:        err.(*echo.HTTPError).Message,
+		})
+	}
+
+	tx := ueh.Application.Database.Begin()
+
+	userResult := tx.Preload("Emails", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at asc")
+	}).First(&user, "id = ?", request.ID)
+
+	if userResult.RowsAffected == 0 {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "02",
+			"error": "Failed To Get User Data",
+		}).Error("failed to get user data")
+
+		tx.Rollback()
+
+		return c.JSON(http.StatusNotFound, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusNotFound),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusNotFound), " ", "_")),
+		})
+	}
+
+	before = userSnapshot(user)
+
+	for i := range user.Emails {
+		if user.Emails[i].ID == request.EmailID {
+			email = &user.Emails[i]
+		} else {
+			emails = append(emails, user.Emails[i])
+		}
+	}
+
+	if email == nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "03",
+			"error": "Failed To Get Email Data",
+		}).Error("failed to get email data")
+
+		tx.Rollback()
+
+		return c.JSON(http.StatusNotFound, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusNotFound),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusNotFound), " ", "_")),
+		})
+	}
+
+	if len(emails) == 0 {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "04",
+			"error": "Last Email Cannot Be Deleted",
+		}).Error("last email cannot be deleted")
+
+		tx.Rollback()
+
+		return c.JSON(http.StatusConflict, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusConflict),
+			Description: "LAST_EMAIL_CANNOT_BE_DELETED",
+		})
+	}
+
+	deleteEmail := tx.Delete(email)
+
+	if deleteEmail.Error != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "05",
+			"error": deleteEmail.Error.Error(),
+		}).Error("failed to delete email data")
+
+		tx.Rollback()
+
+		return c.JSON(http.StatusInternalServerError, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+		})
+	}
+
+	// The oldest remaining email takes over when the primary email is deleted.
+	if email.Primary {
+		if err := ueh.Application.SetPrimaryEmail(tx, emails, emails[0].ID); err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "06",
+				"error": err.Error(),
+			}).Error("failed to set primary email")
+
+			tx.Rollback()
+
+			return c.JSON(http.StatusInternalServerError, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+			})
+		}
+	}
+
+	user.Emails = emails
+
+	if err := ueh.Application.RecordAudit(tx, c, applications.AuditActionEdit, "user", user.ID, before, user); err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "07",
+			"error": err.Error(),
+		}).Error("failed to record audit log")
+
+		tx.Rollback()
+
+		return c.JSON(http.StatusInternalServerError, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+		})
+	}
+
+	tx.Commit()
+
+	return c.JSON(http.StatusOK, types.MainResponse{
+		Code:        fmt.Sprintf("%04d", http.StatusOK),
+		Description: "SUCCESS",
+	})
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MrAndreID/goechoms/applications"
+	"github.com/MrAndreID/goechoms/applications/databases/models"
+	"github.com/MrAndreID/goechoms/applications/encryption"
+	"github.com/MrAndreID/goechoms/applications/types"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestUserSnapshotKeepsEmailsBeforeEdit(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Discard,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var auditLog *models.AuditLog
+
+	err = db.Callback().Create().After("gorm:create").Register("test:audit_log", func(tx *gorm.DB) {
+		if value, ok := tx.Statement.Dest.(*models.AuditLog); ok {
+			auditLog = value
+		}
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := &applications.Application{TimeLocation: time.UTC}
+
+	user := models.User{
+		ID:   "user",
+		Name: "User",
+		Emails: []models.Email{
+			{ID: "first", UserID: "user", Email: "old@example.com", EmailHash: encryption.BlindIndex("old@example.com"), Primary: true},
+		},
+	}
+
+	before := userSnapshot(user)
+
+	if err := app.SyncEmails(db, &user, []types.CreateEmailRequest{{Email: "OLD@example.com"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Emails[0].Email != "OLD@example.com" {
+		t.Fatalf("expected the edited email, got %q", user.Emails[0].Email)
+	}
+
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodPut, "/api/v1/user/user", nil), httptest.NewRecorder())
+
+	if err := app.RecordAudit(db, c, applications.AuditActionEdit, "user", user.ID, before, user); err != nil {
+		t.Fatal(err)
+	}
+
+	if auditLog == nil || auditLog.Before == nil || auditLog.After == nil {
+		t.Fatal("expected an audit log with both snapshots")
+	}
+
+	if !strings.Contains(*auditLog.Before, `"email":"old@example.com"`) {
+		t.Fatalf("expected the old email in the before snapshot, got %s", *auditLog.Before)
+	}
+
+	if !strings.Contains(*auditLog.After, `"email":"OLD@example.com"`) {
+		t.Fatalf("expected the new email in the after snapshot, got %s", *auditLog.After)
+	}
+}
+
+func TestDeleteUserWithoutEmails(t *testing.T) {
+	database := &userTestDatabase{}
+
+	sql.Register("user-test", database)
+
+	connection, err := sql.Open("user-test", "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: connection}), &gorm.Config{Logger: logger.Discard})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := &applications.Application{TimeLocation: time.UTC, Database: db}
+
+	e := echo.New()
+
+	e.Validator = app.NewCustomValidator()
+
+	recorder := httptest.NewRecorder()
+
+	c := e.NewContext(httptest.NewRequest(http.MethodDelete, "/api/v1/user/"+userTestID, nil), recorder)
+
+	c.SetPath("/api/v1/user/:id")
+	c.SetParamNames("id")
+	c.SetParamValues(userTestID)
+
+	if err := NewUserHandler(nil, app).Delete(c); err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected the user to be deleted, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if !database.Committed {
+		t.Fatal("expected the delete to be committed")
+	}
+}
+
+const userTestID string = "0b7f4e4a-4f7e-4a9c-9f2b-6f1d2c3b4a59"
+
+// userTestDatabase is a database with one user without emails, and no other rows.
+type userTestDatabase struct {
+	Committed bool
+}
+
+func (database *userTestDatabase) Open(name string) (driver.Conn, error) {
+	return &userTestConn{Database: database}, nil
+}
+
+type userTestConn struct {
+	Database *userTestDatabase
+}
+
+func (conn *userTestConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (conn *userTestConn) Close() error {
+	return nil
+}
+
+func (conn *userTestConn) Begin() (driver.Tx, error) {
+	return conn, nil
+}
+
+func (conn *userTestConn) Commit() error {
+	conn.Database.Committed = true
+
+	return nil
+}
+
+func (conn *userTestConn) Rollback() error {
+	return nil
+}
+
+func (conn *userTestConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if strings.HasPrefix(query, "SELECT") && strings.Contains(query, `FROM "users"`) {
+		return &userTestRows{Values: [][]driver.Value{{userTestID, "User"}}}, nil
+	}
+
+	return &userTestRows{}, nil
+}
+
+func (conn *userTestConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if strings.Contains(query, `"emails"`) {
+		return driver.RowsAffected(0), nil
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+type userTestRows struct {
+	Values [][]driver.Value
+}
+
+func (rows *userTestRows) Columns() []string {
+	return []string{"id", "name"}
+}
+
+func (rows *userTestRows) Close() error {
+	return nil
+}
+
+func (rows *userTestRows) Next(dest []driver.Value) error {
+	if len(rows.Values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, rows.Values[0])
+
+	rows.Values = rows.Values[1:]
+
+	return nil
+}
@@ -0,0 +1,245 @@
+package applications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/MrAndreID/goechoms/applications/databases/models"
+
+	redisPackage "github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// A record with a zero status code is a lock held by the request in progress.
+type IdempotencyStore interface {
+	// Reserve stores the record unless its key is already used, in which case the stored record is returned.
+	Reserve(ctx context.Context, record *models.IdempotencyKey) (*models.IdempotencyKey, error)
+	Save(ctx context.Context, record *models.IdempotencyKey) error
+	Release(ctx context.Context, keyHash string) error
+	// Extend moves the expiry of a lock that is still held, so it does not expire while its request is in progress.
+	Extend(ctx context.Context, keyHash string, expiresAt time.Time) error
+}
+
+type RedisIdempotencyStore struct {
+	Redis  *redisPackage.Client
+	Prefix string
+}
+
+func NewRedisIdempotencyStore(redisConnection *redisPackage.Client, prefix string) *RedisIdempotencyStore {
+	return &RedisIdempotencyStore{
+		Redis:  redisConnection,
+		Prefix: prefix,
+	}
+}
+
+func (store *RedisIdempotencyStore) Reserve(ctx context.Context, record *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	value, err := json.Marshal(record)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The stored record may expire between SETNX and GET, so the reservation is tried once more.
+	for attempt := 0; attempt < 2; attempt++ {
+		reserved, err := store.Redis.SetNX(ctx, store.Prefix+record.KeyHash, value, time.Until(record.ExpiresAt)).Result()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if reserved {
+			return nil, nil
+		}
+
+		stored, err := store.Redis.Get(ctx, store.Prefix+record.KeyHash).Bytes()
+
+		if errors.Is(err, redisPackage.Nil) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		var existing models.IdempotencyKey
+
+		if err := json.Unmarshal(stored, &existing); err != nil {
+			return nil, err
+		}
+
+		return &existing, nil
+	}
+
+	return nil, errors.New("Failed to Reserve Idempotency Key")
+}
+
+func (store *RedisIdempotencyStore) Save(ctx context.Context, record *models.IdempotencyKey) error {
+	value, err := json.Marshal(record)
+
+	if err != nil {
+		return err
+	}
+
+	return store.Redis.Set(ctx, store.Prefix+record.KeyHash, value, time.Until(record.ExpiresAt)).Err()
+}
+
+func (store *RedisIdempotencyStore) Release(ctx context.Context, keyHash string) error {
+	return store.Redis.Del(ctx, store.Prefix+keyHash).Err()
+}
+
+func (store *RedisIdempotencyStore) Extend(ctx context.Context, keyHash string, expiresAt time.Time) error {
+	return store.Redis.PExpire(ctx, store.Prefix+keyHash, time.Until(expiresAt)).Err()
+}
+
+type DatabaseIdempotencyStore struct {
+	Database  *gorm.DB
+	mutex     sync.Mutex
+	lastSweep time.Time
+}
+
+func NewDatabaseIdempotencyStore(databaseConnection *gorm.DB) *DatabaseIdempotencyStore {
+	return &DatabaseIdempotencyStore{
+		Database: databaseConnection,
+	}
+}
+
+// Expired records are deleted at most once a minute while reserving, or when their key is used again.
+func (store *DatabaseIdempotencyStore) Reserve(ctx context.Context, record *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	store.mutex.Lock()
+
+	sweep := time.Since(store.lastSweep) > time.Minute
+
+	if sweep {
+		store.lastSweep = time.Now()
+	}
+
+	store.mutex.Unlock()
+
+	if sweep {
+		if err := store.Database.WithContext(ctx).Delete(&models.IdempotencyKey{}, "expires_at < ?", time.Now()).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		result := store.Database.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+
+		if result.Error != nil {
+			return nil, result.Error
+		}
+
+		if result.RowsAffected == 1 {
+			return nil, nil
+		}
+
+		var existing models.IdempotencyKey
+
+		err := store.Database.WithContext(ctx).First(&existing, "key_hash = ?", record.KeyHash).Error
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if time.Now().Before(existing.ExpiresAt) {
+			return &existing, nil
+		}
+
+		if err := store.Database.WithContext(ctx).Delete(&models.IdempotencyKey{}, "key_hash = ? AND expires_at = ?", existing.KeyHash, existing.ExpiresAt).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, errors.New("Failed to Reserve Idempotency Key")
+}
+
+func (store *DatabaseIdempotencyStore) Save(ctx context.Context, record *models.IdempotencyKey) error {
+	return store.Database.WithContext(ctx).Save(record).Error
+}
+
+func (store *DatabaseIdempotencyStore) Release(ctx context.Context, keyHash string) error {
+	return store.Database.WithContext(ctx).Delete(&models.IdempotencyKey{}, "key_hash = ?", keyHash).Error
+}
+
+func (store *DatabaseIdempotencyStore) Extend(ctx context.Context, keyHash string, expiresAt time.Time) error {
+	return store.Database.WithContext(ctx).Model(&models.IdempotencyKey{}).Where("key_hash = ? AND status_code = 0", keyHash).Update("expires_at", expiresAt).Error
+}
+
+type MemoryIdempotencyStore struct {
+	mutex     sync.Mutex
+	records   map[string]models.IdempotencyKey
+	lastSweep time.Time
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		records: map[string]models.IdempotencyKey{},
+	}
+}
+
+func (store *MemoryIdempotencyStore) Reserve(ctx context.Context, record *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	var now time.Time = time.Now()
+
+	store.mutex.Lock()
+
+	defer store.mutex.Unlock()
+
+	if now.Sub(store.lastSweep) > time.Minute {
+		for keyHash, existing := range store.records {
+			if now.After(existing.ExpiresAt) {
+				delete(store.records, keyHash)
+			}
+		}
+
+		store.lastSweep = now
+	}
+
+	if existing, ok := store.records[record.KeyHash]; ok && now.Before(existing.ExpiresAt) {
+		return &existing, nil
+	}
+
+	store.records[record.KeyHash] = *record
+
+	return nil, nil
+}
+
+func (store *MemoryIdempotencyStore) Save(ctx context.Context, record *models.IdempotencyKey) error {
+	store.mutex.Lock()
+
+	defer store.mutex.Unlock()
+
+	store.records[record.KeyHash] = *record
+
+	return nil
+}
+
+func (store *MemoryIdempotencyStore) Release(ctx context.Context, keyHash string) error {
+	store.mutex.Lock()
+
+	defer store.mutex.Unlock()
+
+	delete(store.records, keyHash)
+
+	return nil
+}
+
+func (store *MemoryIdempotencyStore) Extend(ctx context.Context, keyHash string, expiresAt time.Time) error {
+	store.mutex.Lock()
+
+	defer store.mutex.Unlock()
+
+	if record, ok := store.records[keyHash]; ok && record.StatusCode == 0 {
+		record.ExpiresAt = expiresAt
+
+		store.records[keyHash] = record
+	}
+
+	return nil
+}
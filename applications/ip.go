@@ -0,0 +1,85 @@
+package applications
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Forwarded headers are only trusted from TRUSTED_PROXIES, without any, the address of the connection is the client IP.
+func (app *Application) NewIPExtractor(cfg *configs.Config) echo.IPExtractor {
+	networks, err := configs.ParseCIDRs(cfg.TrustedProxies)
+
+	if err != nil || len(networks) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, network := range networks {
+		options = append(options, echo.TrustIPRange(network))
+	}
+
+	if http.CanonicalHeaderKey(cfg.ClientIPHeader) == http.CanonicalHeaderKey(echo.HeaderXRealIP) {
+		return echo.ExtractIPFromRealIPHeader(options...)
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+type IPRules struct {
+	Config          *configs.Config
+	AllowList       []*net.IPNet
+	DenyList        []*net.IPNet
+	RouteAllowRules []configs.IPRule
+	RouteDenyRules  []configs.IPRule
+}
+
+func NewIPRules(cfg *configs.Config) (*IPRules, error) {
+	var (
+		rules *IPRules = &IPRules{Config: cfg}
+		err   error
+	)
+
+	if rules.AllowList, err = configs.ParseCIDRs(cfg.IPAllowList); err != nil {
+		return nil, err
+	}
+
+	if rules.DenyList, err = configs.ParseCIDRs(cfg.IPDenyList); err != nil {
+		return nil, err
+	}
+
+	if rules.RouteAllowRules, err = configs.ParseIPRules(cfg.IPRouteAllowList); err != nil {
+		return nil, err
+	}
+
+	if rules.RouteDenyRules, err = configs.ParseIPRules(cfg.IPRouteDenyList); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// The rules are parsed once, and again only after a reload replaced the configuration.
+func (app *Application) IPRules(cfg *configs.Config) (*IPRules, error) {
+	if rules := app.ipRules.Load(); rules != nil && rules.Config == cfg {
+		return rules, nil
+	}
+
+	rules, err := NewIPRules(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	app.ipRules.Store(rules)
+
+	return rules, nil
+}
@@ -1,26 +1,55 @@
 package applications
 
 import (
+	"bytes"
 	"encoding/json"
 
 	jsonIterator "github.com/json-iterator/go"
 	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 )
 
-type CustomJSON struct{}
+type CustomJSON struct {
+	Application *Application
+}
 
 func (app *Application) NewCustomJSON() *CustomJSON {
-	return &CustomJSON{}
+	return &CustomJSON{
+		Application: app,
+	}
 }
 
+// The body is encoded before it is written, so it can be signed when the SignResponse middleware asked for it.
 func (cjson *CustomJSON) Serialize(c echo.Context, i interface{}, indent string) error {
-	enc := json.NewEncoder(c.Response())
+	var buffer bytes.Buffer
+
+	enc := json.NewEncoder(&buffer)
 
 	if indent != "" {
 		enc.SetIndent("", indent)
 	}
 
-	return enc.Encode(i)
+	if err := enc.Encode(i); err != nil {
+		return err
+	}
+
+	// A response that cannot be signed is not sent, the error response that replaces it is sent unsigned.
+	if signResponse, _ := c.Get("SignResponse").(bool); signResponse {
+		if err := cjson.Application.SignResponse(c, buffer.Bytes()); err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   "Applications.JSON.Serialize.01",
+				"error": err.Error(),
+			}).Error("failed to sign response")
+
+			c.Set("SignResponse", false)
+
+			return err
+		}
+	}
+
+	_, err := c.Response().Write(buffer.Bytes())
+
+	return err
 }
 
 func (cjson *CustomJSON) Deserialize(c echo.Context, i interface{}) error {
@@ -0,0 +1,306 @@
+package applications
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+)
+
+type JWTKeySet struct {
+	Secret []byte
+	Keys   map[string]interface{}
+}
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+// The key ID of a PEM file is its file name without the extension.
+func NewJWTKeySet(cfg *configs.Config) (*JWTKeySet, error) {
+	var (
+		tag    string     = "Applications.JWT.NewJWTKeySet."
+		keySet *JWTKeySet = &JWTKeySet{
+			Secret: []byte(cfg.JWTSecret),
+			Keys:   map[string]interface{}{},
+		}
+	)
+
+	for _, path := range cfg.JWTPublicKeyFiles {
+		content, err := os.ReadFile(path)
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "01",
+				"path":  path,
+				"error": err.Error(),
+			}).Error("failed to read jwt public key file")
+
+			return nil, err
+		}
+
+		publicKey, err := parsePublicKey(content)
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "02",
+				"path":  path,
+				"error": err.Error(),
+			}).Error("failed to parse jwt public key file")
+
+			return nil, err
+		}
+
+		keySet.Keys[strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))] = publicKey
+	}
+
+	if cfg.JWTJWKSFile != "" {
+		var jwks struct {
+			Keys []jsonWebKey `json:"keys"`
+		}
+
+		content, err := os.ReadFile(cfg.JWTJWKSFile)
+
+		if err == nil {
+			err = json.Unmarshal(content, &jwks)
+		}
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "03",
+				"path":  cfg.JWTJWKSFile,
+				"error": err.Error(),
+			}).Error("failed to read jwks file")
+
+			return nil, err
+		}
+
+		for _, key := range jwks.Keys {
+			publicKey, err := key.PublicKey()
+
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"tag":   tag + "04",
+					"kid":   key.KeyID,
+					"error": err.Error(),
+				}).Error("failed to parse json web key")
+
+				return nil, err
+			}
+
+			keySet.Keys[key.KeyID] = publicKey
+		}
+	}
+
+	return keySet, nil
+}
+
+func parsePublicKey(content []byte) (interface{}, error) {
+	block, _ := pem.Decode(content)
+
+	if block == nil {
+		return nil, errors.New("Invalid PEM Block")
+	}
+
+	if block.Type == "CERTIFICATE" {
+		certificate, err := x509.ParseCertificate(block.Bytes)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return certificate.PublicKey, nil
+	}
+
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func (key jsonWebKey) PublicKey() (interface{}, error) {
+	decode := func(value string) (*big.Int, error) {
+		decoded, err := base64.RawURLEncoding.DecodeString(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return new(big.Int).SetBytes(decoded), nil
+	}
+
+	switch key.KeyType {
+	case "RSA":
+		n, err := decode(key.N)
+
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decode(key.E)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+
+		switch key.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.New("Curve Not Found")
+		}
+
+		x, err := decode(key.X)
+
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decode(key.Y)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, errors.New("Key Type Not Found")
+}
+
+func (keySet *JWTKeySet) find(kid string, match func(key interface{}) bool) (interface{}, error) {
+	if kid != "" {
+		if key, ok := keySet.Keys[kid]; ok && match(key) {
+			return key, nil
+		}
+
+		return nil, errors.New("Key Not Found")
+	}
+
+	var found interface{}
+
+	for _, key := range keySet.Keys {
+		if !match(key) {
+			continue
+		}
+
+		if found != nil {
+			return nil, errors.New("Key ID Is Required")
+		}
+
+		found = key
+	}
+
+	if found == nil {
+		return nil, errors.New("Key Not Found")
+	}
+
+	return found, nil
+}
+
+func (keySet *JWTKeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if len(keySet.Secret) == 0 {
+			return nil, errors.New("Secret Not Found")
+		}
+
+		return keySet.Secret, nil
+	case *jwt.SigningMethodRSA:
+		return keySet.find(kid, func(key interface{}) bool {
+			_, ok := key.(*rsa.PublicKey)
+
+			return ok
+		})
+	case *jwt.SigningMethodECDSA:
+		return keySet.find(kid, func(key interface{}) bool {
+			_, ok := key.(*ecdsa.PublicKey)
+
+			return ok
+		})
+	}
+
+	return nil, errors.New("Signing Method Not Found")
+}
+
+func (app *Application) ParseJWT(cfg *configs.Config, tokenString string) (jwt.MapClaims, error) {
+	var (
+		claims  jwt.MapClaims = jwt.MapClaims{}
+		options []jwt.ParserOption
+	)
+
+	if app.JWTKeySet == nil {
+		return nil, errors.New("JWT Is Not Initiated")
+	}
+
+	options = append(options,
+		jwt.WithValidMethods(cfg.JWTAlgorithms),
+		jwt.WithLeeway(time.Second*time.Duration(cfg.JWTLeeway)),
+		jwt.WithExpirationRequired(),
+	)
+
+	if cfg.JWTIssuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.JWTIssuer))
+	}
+
+	if cfg.JWTAudience != "" {
+		options = append(options, jwt.WithAudience(cfg.JWTAudience))
+	}
+
+	if _, err := jwt.NewParser(options...).ParseWithClaims(tokenString, claims, app.JWTKeySet.Keyfunc); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// Claims may hold a space separated string (as in the OAuth 2.0 scope claim) or an array of strings.
+func ClaimStrings(claims jwt.MapClaims, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		var values []string
+
+		for _, item := range value {
+			if text, ok := item.(string); ok {
+				values = append(values, text)
+			}
+		}
+
+		return values
+	}
+
+	return nil
+}
@@ -1,9 +1,17 @@
 package applications
 
 import (
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/MrAndreID/goechoms/applications/databases"
+	"github.com/MrAndreID/goechoms/applications/encryption"
+	"github.com/MrAndreID/goechoms/applications/metrics"
 	"github.com/MrAndreID/goechoms/applications/redis"
 	"github.com/MrAndreID/goechoms/applications/services"
 	"github.com/MrAndreID/goechoms/configs"
@@ -15,10 +23,22 @@ import (
 )
 
 type Application struct {
-	TimeLocation *time.Location
-	Database     *gorm.DB
-	Redis        *redisPackage.Client
-	Service      *services.Service
+	ConfigHolder     *configs.Holder
+	TimeLocation     *time.Location
+	Database         *gorm.DB
+	Redis            *redisPackage.Client
+	Metrics          *metrics.Metrics
+	JWTKeySet        *JWTKeySet
+	ReplayStore      ReplayStore
+	RateLimitStore   RateLimitStore
+	IdempotencyStore IdempotencyStore
+	Service          *services.Service
+	Workers          []Worker
+
+	signatureKeys   atomic.Pointer[SignatureKeys]
+	ipRules         atomic.Pointer[IPRules]
+	rateLimits      atomic.Pointer[RateLimits]
+	permissionRules atomic.Pointer[PermissionRules]
 }
 
 func New(cfg *configs.Config) (*Application, error) {
@@ -84,14 +104,169 @@ func New(cfg *configs.Config) (*Application, error) {
 		}
 	}
 
-	return &Application{
-		TimeLocation: timeLocation,
-		Database:     databaseConnection,
-		Redis:        redisConnection,
-		Service:      services.New(cfg, redisConnection, databaseConnection),
-	}, nil
+	configHolder := configs.NewHolder(cfg)
+
+	var metricsCollector *metrics.Metrics
+
+	if cfg.UseMetrics {
+		metricsCollector, err = metrics.New(&metrics.Metric{
+			Namespace:    cfg.MetricsNamespace,
+			DatabaseName: cfg.DatabaseName,
+			Database:     databaseConnection,
+			Redis:        redisConnection,
+		})
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "04",
+				"error": err.Error(),
+			}).Error("failed to initiate metrics")
+
+			return nil, err
+		}
+	}
+
+	var jwtKeySet *JWTKeySet
+
+	if cfg.UseJWT {
+		jwtKeySet, err = NewJWTKeySet(cfg)
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "05",
+				"error": err.Error(),
+			}).Error("failed to initiate jwt key set")
+
+			return nil, err
+		}
+	}
+
+	var (
+		replayStore   ReplayStore
+		signatureKeys *SignatureKeys
+	)
+
+	if cfg.UseSignature {
+		signatureKeys, err = NewSignatureKeys(cfg)
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "06",
+				"error": err.Error(),
+			}).Error("failed to initiate signature keys")
+
+			return nil, err
+		}
+
+		if redisConnection != nil {
+			replayStore = NewRedisReplayStore(redisConnection, "signature-transaction-id:")
+		} else {
+			replayStore = NewMemoryReplayStore(cfg.SignatureReplayCacheSize)
+		}
+	}
+
+	var rateLimitStore RateLimitStore
+
+	if cfg.UseRateLimit {
+		if redisConnection != nil {
+			rateLimitStore = NewRedisRateLimitStore(redisConnection, "rate-limit:")
+		} else {
+			rateLimitStore = NewMemoryRateLimitStore()
+		}
+	}
+
+	var idempotencyStore IdempotencyStore
+
+	if cfg.UseIdempotency {
+		switch cfg.IdempotencyStore {
+		case "redis":
+			idempotencyStore = NewRedisIdempotencyStore(redisConnection, "idempotency-key:")
+		case "database":
+			idempotencyStore = NewDatabaseIdempotencyStore(databaseConnection)
+		default:
+			idempotencyStore = NewMemoryIdempotencyStore()
+		}
+	}
+
+	if cfg.UseFieldEncryption {
+		fieldCipher, err := NewFieldCipher(cfg)
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "07",
+				"error": err.Error(),
+			}).Error("failed to initiate field encryption")
+
+			return nil, err
+		}
+
+		encryption.SetDefault(fieldCipher)
+	}
+
+	app := &Application{
+		ConfigHolder:     configHolder,
+		TimeLocation:     timeLocation,
+		Database:         databaseConnection,
+		Redis:            redisConnection,
+		Metrics:          metricsCollector,
+		JWTKeySet:        jwtKeySet,
+		ReplayStore:      replayStore,
+		RateLimitStore:   rateLimitStore,
+		IdempotencyStore: idempotencyStore,
+		Service:          services.New(cfg, configHolder, redisConnection, databaseConnection, metricsCollector),
+	}
+
+	app.signatureKeys.Store(signatureKeys)
+
+	return app, nil
 }
 
-func (app *Application) Start(cfg *configs.Config, e *echo.Echo) error {
-	return e.Start(":" + cfg.Port)
+func (app *Application) Start(cfg *configs.Config, e *echo.Echo) (int, error) {
+	var (
+		tag         string         = "Applications.Main.Start."
+		quit        chan os.Signal = make(chan os.Signal, 1)
+		serverError chan error     = make(chan error, 1)
+		reason      string
+		exitCode    int = ExitCodeSuccess
+		startError  error
+	)
+
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	defer signal.Stop(quit)
+
+	app.WatchConfig(cfg)
+
+	go func() {
+		if err := e.Start(":" + cfg.Port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverError <- err
+		}
+	}()
+
+	select {
+	case sig := <-quit:
+		reason = "received signal " + sig.String()
+
+		logrus.WithFields(logrus.Fields{
+			"tag":    tag + "01",
+			"signal": sig.String(),
+		}).Info("shutting down application")
+	case err := <-serverError:
+		reason = "server error"
+		exitCode = ExitCodeServerError
+		startError = err
+
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "02",
+			"error": err.Error(),
+		}).Error("failed to start server")
+	}
+
+	shutdownExitCode, err := app.Shutdown(cfg, e, reason)
+
+	if startError != nil {
+		return exitCode, startError
+	}
+
+	return shutdownExitCode, err
 }
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	redisPackage "github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Metrics struct {
+	Registry         *prometheus.Registry
+	RequestsTotal    *prometheus.CounterVec
+	RequestDuration  *prometheus.HistogramVec
+	RequestsInFlight *prometheus.GaugeVec
+	OutboundDuration *prometheus.HistogramVec
+	ErrorsTotal      *prometheus.CounterVec
+}
+
+type Metric struct {
+	Namespace    string
+	DatabaseName string
+	Database     *gorm.DB
+	Redis        *redisPackage.Client
+}
+
+func New(metric *Metric) (*Metrics, error) {
+	var registry *prometheus.Registry = prometheus.NewRegistry()
+
+	m := &Metrics{
+		Registry: registry,
+		RequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metric.Namespace,
+			Name:      "http_requests_total",
+			Help:      "Total number of HTTP requests by route name, method and status code.",
+		}, []string{"route", "method", "code"}),
+		RequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metric.Namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests by route name, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "code"}),
+		RequestsInFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metric.Namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "Number of HTTP requests currently being served by route name.",
+		}, []string{"route"}),
+		OutboundDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metric.Namespace,
+			Name:      "outbound_request_duration_seconds",
+			Help:      "Latency of outbound HTTP requests by service, operation and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"service", "operation", "code"}),
+		ErrorsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metric.Namespace,
+			Name:      "errors_total",
+			Help:      "Total number of internal errors that did not fail a request, by component.",
+		}, []string{"component"}),
+	}
+
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.RequestsTotal,
+		m.RequestDuration,
+		m.RequestsInFlight,
+		m.OutboundDuration,
+		m.ErrorsTotal,
+	)
+
+	if metric.Database != nil {
+		sqlDB, err := metric.Database.DB()
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   "Applications.Metrics.Main.New.01",
+				"error": err.Error(),
+			}).Error("failed to get sql database from gorm")
+
+			return nil, err
+		}
+
+		registry.MustRegister(collectors.NewDBStatsCollector(sqlDB, metric.DatabaseName))
+	}
+
+	if metric.Redis != nil {
+		m.registerRedisPoolStats(metric.Namespace, metric.Redis)
+	}
+
+	return m, nil
+}
+
+func (m *Metrics) registerRedisPoolStats(namespace string, client *redisPackage.Client) {
+	stats := map[string]func(stats *redisPackage.PoolStats) uint32{
+		"hits":        func(stats *redisPackage.PoolStats) uint32 { return stats.Hits },
+		"misses":      func(stats *redisPackage.PoolStats) uint32 { return stats.Misses },
+		"timeouts":    func(stats *redisPackage.PoolStats) uint32 { return stats.Timeouts },
+		"total_conns": func(stats *redisPackage.PoolStats) uint32 { return stats.TotalConns },
+		"idle_conns":  func(stats *redisPackage.PoolStats) uint32 { return stats.IdleConns },
+		"stale_conns": func(stats *redisPackage.PoolStats) uint32 { return stats.StaleConns },
+	}
+
+	for name, value := range stats {
+		value := value
+
+		m.Registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "redis_pool",
+			Name:      name,
+			Help:      "Redis connection pool statistic: " + name + ".",
+		}, func() float64 {
+			return float64(value(client.PoolStats()))
+		}))
+	}
+}
+
+func (m *Metrics) ObserveOutbound(service string, operation string, statusCode int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.OutboundDuration.WithLabelValues(service, operation, strconv.Itoa(statusCode)).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveError(component string) {
+	if m == nil {
+		return
+	}
+
+	m.ErrorsTotal.WithLabelValues(component).Inc()
+}
@@ -0,0 +1,199 @@
+package applications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MrAndreID/goechoms/applications/databases/models"
+	"github.com/MrAndreID/goechoms/applications/types"
+	"github.com/MrAndreID/goechoms/configs"
+
+	redisPackage "github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+// A permission is granted by "*", by itself, or by a wildcard on its prefix, e.g. "user:*" grants "user:write".
+func HasPermission(permissions []string, permission string) bool {
+	for _, value := range permissions {
+		if value == "*" || value == permission {
+			return true
+		}
+
+		if prefix, ok := strings.CutSuffix(value, "*"); ok && strings.HasPrefix(permission, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PermissionRules holds the parsed permission rules of a configuration, so they are parsed once, and again only after a reload replaced it.
+type PermissionRules struct {
+	Config *configs.Config
+	Roles  map[string][]string
+	Routes map[string][]string
+}
+
+func NewPermissionRules(cfg *configs.Config) (*PermissionRules, error) {
+	var (
+		rules *PermissionRules = &PermissionRules{Config: cfg}
+		err   error
+	)
+
+	if rules.Roles, err = configs.ParsePermissionRules(cfg.PermissionRoles); err != nil {
+		return nil, err
+	}
+
+	if rules.Routes, err = configs.ParsePermissionRules(cfg.PermissionRoutes); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Routes without a rule in PERMISSION_ROUTES do not require any permission.
+func (rules *PermissionRules) Route(routeName string) []string {
+	return rules.Routes[routeName]
+}
+
+// RouteAllowed checks the route names of service keys and the rules of PERMISSION_ROUTES, the scopes required by the routes themselves are not checked.
+func (rules *PermissionRules) RouteAllowed(identity *types.Identity, permissions []string, routeName string) bool {
+	if identity.Type == "service-key" && !identity.HasScope(routeName) {
+		return false
+	}
+
+	if !rules.Config.UsePermission {
+		return true
+	}
+
+	for _, permission := range rules.Route(routeName) {
+		if !HasPermission(permissions, permission) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (app *Application) PermissionRules(cfg *configs.Config) (*PermissionRules, error) {
+	if rules := app.permissionRules.Load(); rules != nil && rules.Config == cfg {
+		return rules, nil
+	}
+
+	rules, err := NewPermissionRules(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	app.permissionRules.Store(rules)
+
+	return rules, nil
+}
+
+func (app *Application) IdentityPermissions(ctx context.Context, identity *types.Identity) ([]string, error) {
+	return app.RolePermissions(ctx, identity.Roles)
+}
+
+func (app *Application) RolePermissions(ctx context.Context, roles []string) ([]string, error) {
+	var (
+		cfg         *configs.Config = app.ConfigHolder.Get()
+		permissions []string
+		seen        map[string]struct{} = map[string]struct{}{}
+	)
+
+	for _, role := range roles {
+		var (
+			rolePermissions []string
+			err             error
+		)
+
+		if cfg.PermissionStore == "database" {
+			rolePermissions, err = app.findDatabaseRolePermissions(ctx, cfg, role)
+		} else {
+			rolePermissions, err = app.findConfigRolePermissions(cfg, role)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, permission := range rolePermissions {
+			if _, ok := seen[permission]; !ok {
+				seen[permission] = struct{}{}
+
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+
+	sort.Strings(permissions)
+
+	return permissions, nil
+}
+
+func (app *Application) findConfigRolePermissions(cfg *configs.Config, role string) ([]string, error) {
+	rules, err := app.PermissionRules(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rules.Roles[role], nil
+}
+
+func (app *Application) findDatabaseRolePermissions(ctx context.Context, cfg *configs.Config, role string) ([]string, error) {
+	var (
+		tag         string        = "Applications.Permission.FindDatabaseRolePermissions."
+		cacheKey    string        = "role-permissions:" + role
+		cacheTTL    time.Duration = time.Second * time.Duration(cfg.PermissionCacheTTL)
+		permissions []string
+	)
+
+	if app.Redis != nil && cacheTTL > 0 {
+		cached, err := app.Redis.Get(ctx, cacheKey).Bytes()
+
+		if err == nil && json.Unmarshal(cached, &permissions) == nil {
+			return permissions, nil
+		}
+
+		if err != nil && !errors.Is(err, redisPackage.Nil) {
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"tag":   tag + "01",
+				"error": err.Error(),
+			}).Error("failed to get role permissions from redis")
+		}
+	}
+
+	result := app.Database.WithContext(ctx).
+		Model(&models.RolePermission{}).
+		Joins("JOIN roles ON roles.id = role_permissions.role_id AND roles.deleted_at IS NULL").
+		Where("roles.name = ?", role).
+		Pluck("role_permissions.permission", &permissions)
+
+	if result.Error != nil {
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"tag":   tag + "02",
+			"error": result.Error.Error(),
+		}).Error("failed to get role permissions from database")
+
+		return nil, result.Error
+	}
+
+	if app.Redis != nil && cacheTTL > 0 {
+		cached, _ := json.Marshal(permissions)
+
+		if err := app.Redis.Set(ctx, cacheKey, cached, cacheTTL).Err(); err != nil {
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"tag":   tag + "03",
+				"error": err.Error(),
+			}).Error("failed to set role permissions to redis")
+		}
+	}
+
+	return permissions, nil
+}
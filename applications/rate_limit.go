@@ -0,0 +1,203 @@
+package applications
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/MrAndreID/goechoms/configs"
+
+	redisPackage "github.com/go-redis/redis/v8"
+)
+
+type RateLimitStore interface {
+	// Increment counts a hit in the current window and returns the hits of the current and the previous window.
+	Increment(ctx context.Context, key string, window time.Duration, now time.Time) (int64, int64, error)
+}
+
+// RateLimits holds the parsed rate limits of a configuration, so they are parsed once, and again only after a reload replaced it.
+type RateLimits struct {
+	Config  *configs.Config
+	Default *configs.RateLimit
+	IP      *configs.RateLimit
+	Routes  map[string]configs.RateLimit
+}
+
+func NewRateLimits(cfg *configs.Config) (*RateLimits, error) {
+	defaultRateLimit, routes, err := configs.ParseRateLimits(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimits := &RateLimits{
+		Config:  cfg,
+		Default: defaultRateLimit,
+		Routes:  routes,
+	}
+
+	if cfg.RateLimitIP != "" {
+		rateLimit, err := configs.ParseRateLimit(cfg.RateLimitIP)
+
+		if err != nil {
+			return nil, err
+		}
+
+		rateLimits.IP = &rateLimit
+	}
+
+	return rateLimits, nil
+}
+
+func (rateLimits *RateLimits) Route(routeName string) (configs.RateLimit, bool) {
+	if rateLimit, ok := rateLimits.Routes[routeName]; ok {
+		return rateLimit, true
+	}
+
+	if rateLimits.Default != nil {
+		return *rateLimits.Default, true
+	}
+
+	return configs.RateLimit{}, false
+}
+
+func (app *Application) RateLimits(cfg *configs.Config) (*RateLimits, error) {
+	if rateLimits := app.rateLimits.Load(); rateLimits != nil && rateLimits.Config == cfg {
+		return rateLimits, nil
+	}
+
+	rateLimits, err := NewRateLimits(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	app.rateLimits.Store(rateLimits)
+
+	return rateLimits, nil
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Duration
+}
+
+func rateLimitWindowKey(key string, window time.Duration, index int64) string {
+	return key + ":" + strconv.FormatInt(int64(window/time.Second), 10) + ":" + strconv.FormatInt(index, 10)
+}
+
+type RedisRateLimitStore struct {
+	Redis  *redisPackage.Client
+	Prefix string
+}
+
+func NewRedisRateLimitStore(redisConnection *redisPackage.Client, prefix string) *RedisRateLimitStore {
+	return &RedisRateLimitStore{
+		Redis:  redisConnection,
+		Prefix: prefix,
+	}
+}
+
+func (store *RedisRateLimitStore) Increment(ctx context.Context, key string, window time.Duration, now time.Time) (int64, int64, error) {
+	var index int64 = now.UnixNano() / int64(window)
+
+	pipeline := store.Redis.TxPipeline()
+
+	current := pipeline.Incr(ctx, store.Prefix+rateLimitWindowKey(key, window, index))
+	pipeline.Expire(ctx, store.Prefix+rateLimitWindowKey(key, window, index), window*2)
+	previous := pipeline.Get(ctx, store.Prefix+rateLimitWindowKey(key, window, index-1))
+
+	if _, err := pipeline.Exec(ctx); err != nil && !errors.Is(err, redisPackage.Nil) {
+		return 0, 0, err
+	}
+
+	previousHits, err := previous.Int64()
+
+	if err != nil && !errors.Is(err, redisPackage.Nil) {
+		return 0, 0, err
+	}
+
+	return current.Val(), previousHits, nil
+}
+
+type memoryRateLimitEntry struct {
+	Hits      int64
+	ExpiresAt time.Time
+}
+
+type MemoryRateLimitStore struct {
+	mutex     sync.Mutex
+	entries   map[string]*memoryRateLimitEntry
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		entries: map[string]*memoryRateLimitEntry{},
+	}
+}
+
+// Expired windows are swept at most once a minute while counting, so no background worker is needed.
+func (store *MemoryRateLimitStore) Increment(ctx context.Context, key string, window time.Duration, now time.Time) (int64, int64, error) {
+	var index int64 = now.UnixNano() / int64(window)
+
+	store.mutex.Lock()
+
+	defer store.mutex.Unlock()
+
+	if now.Sub(store.lastSweep) > time.Minute {
+		for entryKey, entry := range store.entries {
+			if now.After(entry.ExpiresAt) {
+				delete(store.entries, entryKey)
+			}
+		}
+
+		store.lastSweep = now
+	}
+
+	currentKey := rateLimitWindowKey(key, window, index)
+
+	current, ok := store.entries[currentKey]
+
+	if !ok {
+		current = &memoryRateLimitEntry{ExpiresAt: now.Add(window * 2)}
+
+		store.entries[currentKey] = current
+	}
+
+	current.Hits++
+
+	var previousHits int64
+
+	if previous, ok := store.entries[rateLimitWindowKey(key, window, index-1)]; ok {
+		previousHits = previous.Hits
+	}
+
+	return current.Hits, previousHits, nil
+}
+
+// The sliding window weighs the hits of the previous window by how much of it still overlaps the last window duration.
+func (app *Application) CheckRateLimit(ctx context.Context, key string, rateLimit configs.RateLimit) (*RateLimitResult, error) {
+	var now time.Time = time.Now()
+
+	current, previous, err := app.RateLimitStore.Increment(ctx, key, rateLimit.Window, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	elapsed := time.Duration(now.UnixNano() % int64(rateLimit.Window))
+	hits := float64(previous)*(1-float64(elapsed)/float64(rateLimit.Window)) + float64(current)
+
+	return &RateLimitResult{
+		Allowed:   hits <= float64(rateLimit.Limit),
+		Limit:     rateLimit.Limit,
+		Remaining: int(math.Max(0, math.Floor(float64(rateLimit.Limit)-hits))),
+		Reset:     rateLimit.Window - elapsed,
+	}, nil
+}
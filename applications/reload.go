@@ -0,0 +1,112 @@
+package applications
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/sirupsen/logrus"
+)
+
+func (app *Application) WatchConfig(cfg *configs.Config) {
+	var (
+		tag     string         = "Applications.Reload.WatchConfig."
+		hangup  chan os.Signal = make(chan os.Signal, 1)
+		done    chan struct{}  = make(chan struct{})
+		stopped chan struct{}  = make(chan struct{})
+		tick    <-chan time.Time
+		ticker  *time.Ticker
+	)
+
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	if cfg.ConfigWatchInterval > 0 {
+		ticker = time.NewTicker(time.Second * time.Duration(cfg.ConfigWatchInterval))
+		tick = ticker.C
+	}
+
+	modTimes := configModTimes(cfg)
+
+	// A failed reload keeps the current configuration, so it is only visible in the log and the errors_total metric.
+	reload := func() {
+		if err := app.ConfigHolder.Reload(); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "01",
+				"error": err.Error(),
+			}).Error("failed to reload configuration, keeping the current configuration")
+
+			app.Metrics.ObserveError("config_reload")
+		}
+	}
+
+	go func() {
+		defer close(stopped)
+
+		for {
+			select {
+			case <-hangup:
+				logrus.WithFields(logrus.Fields{
+					"tag": tag + "02",
+				}).Info("received SIGHUP, reloading configuration")
+
+				reload()
+			case <-tick:
+				current := configModTimes(cfg)
+
+				for path, modTime := range current {
+					if !modTime.Equal(modTimes[path]) {
+						logrus.WithFields(logrus.Fields{
+							"tag":  tag + "03",
+							"path": path,
+						}).Info("configuration file changed, reloading configuration")
+
+						reload()
+
+						break
+					}
+				}
+
+				modTimes = current
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	app.RegisterWorker("config watcher", func(ctx context.Context) error {
+		signal.Stop(hangup)
+
+		if ticker != nil {
+			ticker.Stop()
+		}
+
+		close(done)
+
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+func configModTimes(cfg *configs.Config) map[string]time.Time {
+	modTimes := map[string]time.Time{}
+
+	for _, path := range []string{cfg.ConfigFile, ".env"} {
+		if path == "" {
+			continue
+		}
+
+		if fileInfo, err := os.Stat(path); err == nil {
+			modTimes[path] = fileInfo.ModTime()
+		}
+	}
+
+	return modTimes
+}
@@ -0,0 +1,141 @@
+package applications
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MrAndreID/goechoms/configs"
+
+	redisPackage "github.com/go-redis/redis/v8"
+)
+
+var (
+	ErrTransactionIDReplayed         = errors.New("Transaction ID Replayed")
+	ErrTransactionIDExpired          = errors.New("Transaction ID Expired")
+	ErrTransactionIDTimestampMissing = errors.New("Transaction ID Timestamp Missing")
+)
+
+type ReplayStore interface {
+	// Remember reports whether the id was not seen before within the ttl.
+	Remember(ctx context.Context, id string, ttl time.Duration) (bool, error)
+}
+
+type RedisReplayStore struct {
+	Redis  *redisPackage.Client
+	Prefix string
+}
+
+func NewRedisReplayStore(redisConnection *redisPackage.Client, prefix string) *RedisReplayStore {
+	return &RedisReplayStore{
+		Redis:  redisConnection,
+		Prefix: prefix,
+	}
+}
+
+func (store *RedisReplayStore) Remember(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	sum := sha256.Sum256([]byte(id))
+
+	return store.Redis.SetNX(ctx, store.Prefix+hex.EncodeToString(sum[:]), 1, ttl).Result()
+}
+
+type memoryReplayEntry struct {
+	ID        string
+	ExpiresAt time.Time
+}
+
+type MemoryReplayStore struct {
+	Capacity int
+	mutex    sync.Mutex
+	entries  *list.List
+	items    map[string]*list.Element
+}
+
+func NewMemoryReplayStore(capacity int) *MemoryReplayStore {
+	return &MemoryReplayStore{
+		Capacity: capacity,
+		entries:  list.New(),
+		items:    map[string]*list.Element{},
+	}
+}
+
+// When the store is full the least recently seen id is evicted, so the capacity must cover the traffic within the ttl.
+func (store *MemoryReplayStore) Remember(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	var now time.Time = time.Now()
+
+	store.mutex.Lock()
+
+	defer store.mutex.Unlock()
+
+	if element, ok := store.items[id]; ok {
+		entry := element.Value.(*memoryReplayEntry)
+
+		if now.Before(entry.ExpiresAt) {
+			store.entries.MoveToFront(element)
+
+			return false, nil
+		}
+
+		store.entries.Remove(element)
+
+		delete(store.items, id)
+	}
+
+	for store.entries.Len() > 0 {
+		oldest := store.entries.Back()
+		entry := oldest.Value.(*memoryReplayEntry)
+
+		if store.entries.Len() < store.Capacity && now.Before(entry.ExpiresAt) {
+			break
+		}
+
+		store.entries.Remove(oldest)
+
+		delete(store.items, entry.ID)
+	}
+
+	store.items[id] = store.entries.PushFront(&memoryReplayEntry{
+		ID:        id,
+		ExpiresAt: now.Add(ttl),
+	})
+
+	return true, nil
+}
+
+// A transaction ID may embed the unix time it was created at as a suffix, e.g. "<id>:1700000000".
+func (app *Application) CheckTransactionID(ctx context.Context, transactionID string) error {
+	var cfg *configs.Config = app.ConfigHolder.Get()
+
+	if cfg.SignatureTimestamp != "disabled" {
+		index := strings.LastIndex(transactionID, ":")
+
+		timestamp, err := strconv.ParseInt(transactionID[index+1:], 10, 64)
+
+		switch {
+		case index < 0 || err != nil:
+			if cfg.SignatureTimestamp == "required" {
+				return ErrTransactionIDTimestampMissing
+			}
+		case time.Since(time.Unix(timestamp, 0)).Abs() > time.Second*time.Duration(cfg.SignatureClockSkew):
+			return ErrTransactionIDExpired
+		}
+	}
+
+	remembered, err := app.ReplayStore.Remember(ctx, transactionID, time.Second*time.Duration(cfg.SignatureReplayTTL))
+
+	if err != nil {
+		return err
+	}
+
+	if !remembered {
+		return ErrTransactionIDReplayed
+	}
+
+	return nil
+}
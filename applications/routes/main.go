@@ -11,20 +11,28 @@ import (
 	"github.com/MrAndreID/goechoms/applications/types"
 	"github.com/MrAndreID/goechoms/configs"
 
-	loggerUtil "github.com/hlmn/senyum-go-utils/logger/echo"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/labstack/gommon/log"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	"github.com/unrolled/secure"
 	"go.elastic.co/apm/module/apmechov4"
 )
 
+var echoLogLevels map[string]log.Lvl = map[string]log.Lvl{
+	"debug": log.DEBUG,
+	"info":  log.INFO,
+	"warn":  log.WARN,
+	"error": log.ERROR,
+	"off":   log.OFF,
+}
+
 func New(cfg *configs.Config, app *applications.Application) *echo.Echo {
 	var tag string = "Applications.Routes.Main.New."
 
 	echo.NotFoundHandler = func(c echo.Context) error {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag": tag + "01",
 		}).Error("route not found")
 
@@ -35,7 +43,7 @@ func New(cfg *configs.Config, app *applications.Application) *echo.Echo {
 	}
 
 	echo.MethodNotAllowedHandler = func(c echo.Context) error {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 			"tag": tag + "02",
 		}).Error("method not allowed")
 
@@ -49,14 +57,16 @@ func New(cfg *configs.Config, app *applications.Application) *echo.Echo {
 
 	e.Validator = app.NewCustomValidator()
 
-	e.Logger.SetLevel(log.DEBUG)
+	e.Logger.SetLevel(echoLogLevels[cfg.EchoLogLevel])
 
-	e.Debug = true
+	e.Debug = cfg.AppDebug
 
 	e.HTTPErrorHandler = app.NewCustomHTTPErrorHandler
 
 	e.JSONSerializer = app.NewCustomJSON()
 
+	e.IPExtractor = app.NewIPExtractor(cfg)
+
 	e.Pre(middleware.RemoveTrailingSlash())
 
 	middlewares := middlewares.NewCustomMiddleware(cfg, app)
@@ -65,48 +75,37 @@ func New(cfg *configs.Config, app *applications.Application) *echo.Echo {
 
 	e.Use(apmechov4.Middleware())
 
+	e.Use(middlewares.SetRouteName)
+
+	if cfg.UseMetrics {
+		e.Use(middlewares.Metrics)
+	}
+
 	e.Use(middleware.Recover())
 
-	e.Use(middleware.BodyDump(func(c echo.Context, requestBody, responseBody []byte) {
-		request := struct {
-			Header interface{} `json:"header"`
-			Body   string      `json:"body"`
-		}{
-			Header: c.Request().Header,
-			Body:   string(requestBody),
-		}
-
-		response := struct {
-			Header interface{} `json:"header"`
-			Body   string      `json:"body"`
-		}{
-			Header: c.Response().Header(),
-			Body:   string(responseBody),
-		}
-
-		loggerUtil.Info(c, logrus.Fields{
-			"request":   request,
-			"requestId": c.Get("RequestID"),
-			"response":  response,
-			"url":       c.Request().Host + c.Request().URL.String(),
-		}, "body dump")
-	}))
+	if cfg.LogBodyDump {
+		e.Use(middlewares.BodyDump())
+	}
+
+	e.Use(middlewares.IPFilter)
 
 	e.Use(middleware.SecureWithConfig(middleware.SecureConfig{
 		XSSProtection:         "1; mode=block",
 		ContentTypeNosniff:    "nosniff",
 		XFrameOptions:         "SAMEORIGIN",
-		HSTSMaxAge:            3600,
-		ContentSecurityPolicy: "default-src 'self'",
+		HSTSMaxAge:            cfg.HSTSMaxAge,
+		HSTSExcludeSubdomains: !cfg.HSTSIncludeSubdomains,
+		HSTSPreloadEnabled:    cfg.HSTSPreload,
+		ContentSecurityPolicy: cfg.ContentSecurityPolicy,
 	}))
 
 	secureMiddleware := secure.Options{
 		SSLProxyHeaders:      map[string]string{"X-Forwarded-Proto": "https"},
-		STSSeconds:           63072000,
-		STSIncludeSubdomains: true,
-		STSPreload:           true,
+		STSSeconds:           int64(cfg.HSTSMaxAge),
+		STSIncludeSubdomains: cfg.HSTSIncludeSubdomains,
+		STSPreload:           cfg.HSTSPreload,
 		ForceSTSHeader:       true,
-		IsDevelopment:        true,
+		IsDevelopment:        cfg.SecureDevelopment,
 	}
 
 	e.Use(echo.WrapMiddleware(secure.New(secureMiddleware).Handler))
@@ -114,11 +113,23 @@ func New(cfg *configs.Config, app *applications.Application) *echo.Echo {
 	e.Use(middleware.Logger())
 
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: cfg.AllowedOrigins,
+		AllowOriginFunc: func(origin string) (bool, error) {
+			for _, allowedOrigin := range app.ConfigHolder.Get().AllowedOrigins {
+				if allowedOrigin == "*" || strings.EqualFold(allowedOrigin, origin) {
+					return true, nil
+				}
+			}
+
+			return false, nil
+		},
 		AllowHeaders: []string{"*"},
 		AllowMethods: []string{echo.GET, echo.HEAD, echo.PUT, echo.PATCH, echo.POST, echo.DELETE},
 	}))
 
+	if cfg.UseResponseSignature {
+		e.Use(middlewares.SignResponse)
+	}
+
 	if cfg.UseSignature {
 		e.Use(middlewares.SignatureCheck)
 	}
@@ -127,15 +138,33 @@ func New(cfg *configs.Config, app *applications.Application) *echo.Echo {
 
 	handler := handlers.New(cfg, app)
 
-	v1 := e.Group("/api/v1")
+	healthRoute := e.Group("/health")
+	healthRoute.GET("/live", handler.Health.Live).Name = "health.live"
+	healthRoute.GET("/ready", handler.Health.Ready).Name = "health.ready"
 
-	userRoute := v1.Group("/user")
-	userRoute.GET("", handler.User.Index, middlewares.ServiceKeyCheck).Name = "user.index"
-	userRoute.POST("", handler.User.Create, middlewares.ServiceKeyCheck).Name = "user.create"
-	userRoute.PUT("/:id", handler.User.Edit, middlewares.ServiceKeyCheck).Name = "user.edit"
-	userRoute.DELETE("/:id", handler.User.Delete, middlewares.ServiceKeyCheck).Name = "user.delete"
+	if cfg.UseMetrics {
+		e.GET("/metrics", echo.WrapHandler(promhttp.HandlerFor(app.Metrics.Registry, promhttp.HandlerOpts{}))).Name = "metrics"
+	}
+
+	v1 := e.Group("/api/v1", middlewares.IPRateLimit)
 
-	v1.GET("/currency", handler.Currency.Index).Name = "currency.index"
+	userRoute := v1.Group("/user")
+	userRoute.GET("", handler.User.Index, middlewares.ServiceKeyOrJWTCheck, middlewares.RateLimit, middlewares.PermissionCheck, middlewares.RequireScopes("user:read")).Name = "user.index"
+	userRoute.POST("", handler.User.Create, middlewares.ServiceKeyOrJWTCheck, middlewares.RateLimit, middlewares.PermissionCheck, middlewares.RequireScopes("user:write"), middlewares.Idempotency).Name = "user.create"
+	userRoute.GET("/:id", handler.User.Show, middlewares.ServiceKeyOrJWTCheck, middlewares.RateLimit, middlewares.PermissionCheck, middlewares.RequireScopes("user:read")).Name = "user.show"
+	userRoute.PUT("/:id", handler.User.Edit, middlewares.ServiceKeyOrJWTCheck, middlewares.RateLimit, middlewares.PermissionCheck, middlewares.RequireScopes("user:write"), middlewares.Idempotency).Name = "user.edit"
+	userRoute.DELETE("/:id", handler.User.Delete, middlewares.ServiceKeyOrJWTCheck, middlewares.RateLimit, middlewares.PermissionCheck, middlewares.RequireScopes("user:delete"), middlewares.Idempotency).Name = "user.delete"
+	userRoute.GET("/:id/emails", handler.UserEmail.Index, middlewares.ServiceKeyOrJWTCheck, middlewares.RateLimit, middlewares.PermissionCheck, middlewares.RequireScopes("user:read")).Name = "user.email.index"
+	userRoute.GET("/:id/emails/:emailId", handler.UserEmail.Show, middlewares.ServiceKeyOrJWTCheck, middlewares.RateLimit, middlewares.PermissionCheck, middlewares.RequireScopes("user:read")).Name = "user.email.show"
+	userRoute.POST("/:id/emails", handler.UserEmail.Create, middlewares.ServiceKeyOrJWTCheck, middlewares.RateLimit, middlewares.PermissionCheck, middlewares.RequireScopes("user:write"), middlewares.Idempotency).Name = "user.email.create"
+	userRoute.DELETE("/:id/emails/:emailId", handler.UserEmail.Delete, middlewares.ServiceKeyOrJWTCheck, middlewares.RateLimit, middlewares.PermissionCheck, middlewares.RequireScopes("user:write"), middlewares.Idempotency).Name = "user.email.delete"
+
+	v1.GET("/audit-log", handler.AuditLog.Index, middlewares.ServiceKeyOrJWTCheck, middlewares.RateLimit, middlewares.PermissionCheck, middlewares.RequireScopes("audit:read")).Name = "audit-log.index"
+
+	adminRoute := v1.Group("/admin")
+	adminRoute.GET("/permission", handler.Permission.Show, middlewares.ServiceKeyOrJWTCheck, middlewares.RateLimit, middlewares.PermissionCheck, middlewares.RequirePermissions("permission:read")).Name = "admin.permission.show"
+
+	v1.GET("/currency", handler.Currency.Index, middlewares.RateLimit).Name = "currency.index"
 
 	routes := e.Routes()
 
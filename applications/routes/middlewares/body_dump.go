@@ -0,0 +1,276 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	loggerUtil "github.com/hlmn/senyum-go-utils/logger/echo"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+type redactRule struct {
+	Path  []string
+	Style string
+}
+
+func (cm *CustomMiddleware) BodyDump() echo.MiddlewareFunc {
+	var (
+		headers    map[string]string   = map[string]string{}
+		skipRoutes map[string]struct{} = map[string]struct{}{}
+		fields     []redactRule
+	)
+
+	parseStyled := func(value string) (string, string) {
+		index := strings.LastIndex(value, ":")
+
+		if index > 0 {
+			switch value[index+1:] {
+			case "full", "partial", "hash":
+				return strings.TrimSpace(value[:index]), value[index+1:]
+			}
+		}
+
+		return strings.TrimSpace(value), cm.Config.LogRedactStyle
+	}
+
+	for _, value := range append(cm.Config.LogRedactHeaders, cm.Config.SignatureName, cm.Config.SignatureTransactionIDName) {
+		name, style := parseStyled(value)
+
+		if name != "" {
+			headers[http.CanonicalHeaderKey(name)] = style
+		}
+	}
+
+	for _, value := range cm.Config.LogRedactFields {
+		path, style := parseStyled(value)
+
+		if path != "" {
+			fields = append(fields, redactRule{
+				Path:  strings.Split(path, "."),
+				Style: style,
+			})
+		}
+	}
+
+	for _, routeName := range cm.Config.LogBodyDumpSkipRoutes {
+		skipRoutes[strings.TrimSpace(routeName)] = struct{}{}
+	}
+
+	return middleware.BodyDumpWithConfig(middleware.BodyDumpConfig{
+		Skipper: func(c echo.Context) bool {
+			_, ok := skipRoutes[cm.RouteName(c)]
+
+			return ok
+		},
+		Handler: func(c echo.Context, requestBody, responseBody []byte) {
+			request := struct {
+				Header interface{} `json:"header"`
+				Body   string      `json:"body"`
+			}{
+				Header: redactHeaders(c.Request().Header, headers),
+				Body:   cm.dumpBody(c.Request().Header.Get(echo.HeaderContentType), requestBody, fields),
+			}
+
+			response := struct {
+				Header interface{} `json:"header"`
+				Body   string      `json:"body"`
+			}{
+				Header: redactHeaders(c.Response().Header(), headers),
+				Body:   cm.dumpBody(c.Response().Header().Get(echo.HeaderContentType), responseBody, fields),
+			}
+
+			loggerUtil.Info(c, logrus.Fields{
+				"tag":       "Applications.Routes.Middlewares.BodyDump.BodyDump.01",
+				"ip":        c.RealIP(),
+				"request":   request,
+				"requestId": c.Get("RequestID"),
+				"response":  response,
+				"url":       c.Request().Host + c.Request().URL.String(),
+			}, "body dump")
+		},
+	})
+}
+
+func (cm *CustomMiddleware) dumpBody(contentType string, body []byte, fields []redactRule) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	if !isTextMediaType(mediaType, body) {
+		return fmt.Sprintf("[SKIPPED %s %d BYTES]", mediaType, len(body))
+	}
+
+	if len(fields) > 0 && (mediaType == "" || mediaType == echo.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json")) {
+		var payload interface{}
+
+		if err := json.Unmarshal(body, &payload); err == nil {
+			if redacted, err := json.Marshal(redactValue(payload, nil, fields)); err == nil {
+				body = redacted
+			}
+		}
+	}
+
+	if len(fields) > 0 && mediaType == echo.MIMEApplicationForm {
+		body = redactForm(body, fields)
+	}
+
+	// The body is cut at the start of a rune, so a multi-byte character is not split.
+	if cm.Config.LogBodyMaxSize > 0 && len(body) > cm.Config.LogBodyMaxSize {
+		size := cm.Config.LogBodyMaxSize
+
+		for size > 0 && !utf8.RuneStart(body[size]) {
+			size--
+		}
+
+		return fmt.Sprintf("%s...[TRUNCATED %d BYTES]", body[:size], len(body)-size)
+	}
+
+	return string(body)
+}
+
+func isTextMediaType(mediaType string, body []byte) bool {
+	switch {
+	case mediaType == "":
+		return utf8.Valid(body)
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case mediaType == echo.MIMEApplicationJSON, mediaType == echo.MIMEApplicationXML, mediaType == echo.MIMEApplicationForm:
+		return true
+	case strings.HasSuffix(mediaType, "+json"), strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+
+	return false
+}
+
+func redactHeaders(header http.Header, rules map[string]string) map[string][]string {
+	result := make(map[string][]string, len(header))
+
+	for name, values := range header {
+		style, ok := rules[http.CanonicalHeaderKey(name)]
+
+		if !ok {
+			result[name] = values
+
+			continue
+		}
+
+		masked := make([]string, len(values))
+
+		for i, value := range values {
+			masked[i] = maskValue(value, style)
+		}
+
+		result[name] = masked
+	}
+
+	return result
+}
+
+// Array elements are transparent to the path, and a rule matches when it is a suffix of the field path.
+func redactValue(value interface{}, path []string, rules []redactRule) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			childPath := append(append([]string{}, path...), key)
+
+			if style, ok := matchRedactRule(childPath, rules); ok {
+				typed[key] = maskValue(fmt.Sprint(child), style)
+
+				continue
+			}
+
+			typed[key] = redactValue(child, childPath, rules)
+		}
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = redactValue(child, path, rules)
+		}
+	}
+
+	return value
+}
+
+// Fields are redacted in place, so the order of the form is kept.
+func redactForm(body []byte, rules []redactRule) []byte {
+	pairs := strings.Split(string(body), "&")
+
+	for i, pair := range pairs {
+		key, value, _ := strings.Cut(pair, "=")
+
+		name, err := url.QueryUnescape(key)
+
+		if err != nil {
+			continue
+		}
+
+		style, ok := matchRedactRule([]string{name}, rules)
+
+		if !ok {
+			continue
+		}
+
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			value = unescaped
+		}
+
+		pairs[i] = key + "=" + url.QueryEscape(maskValue(value, style))
+	}
+
+	return []byte(strings.Join(pairs, "&"))
+}
+
+func matchRedactRule(path []string, rules []redactRule) (string, bool) {
+	for _, rule := range rules {
+		if len(rule.Path) > len(path) {
+			continue
+		}
+
+		offset := len(path) - len(rule.Path)
+		matched := true
+
+		for i, segment := range rule.Path {
+			if segment != "*" && !strings.EqualFold(segment, path[offset+i]) {
+				matched = false
+
+				break
+			}
+		}
+
+		if matched {
+			return rule.Style, true
+		}
+	}
+
+	return "", false
+}
+
+func maskValue(value string, style string) string {
+	switch style {
+	case "partial":
+		runes := []rune(value)
+
+		if len(runes) <= 6 {
+			return strings.Repeat("*", len(runes))
+		}
+
+		return string(runes[:2]) + strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-2:])
+	case "hash":
+		sum := sha256.Sum256([]byte(value))
+
+		return "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	return "[REDACTED]"
+}
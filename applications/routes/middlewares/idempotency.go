@@ -0,0 +1,268 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MrAndreID/goechoms/applications/databases/models"
+	"github.com/MrAndreID/goechoms/applications/types"
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// Headers that belong to a single response are not replayed.
+var idempotencySkipHeaders map[string]struct{} = map[string]struct{}{
+	"Content-Length":        {},
+	"Date":                  {},
+	"Retry-After":           {},
+	"X-Ratelimit-Limit":     {},
+	"X-Ratelimit-Remaining": {},
+	"X-Ratelimit-Reset":     {},
+	"X-Request-Id":          {},
+}
+
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	Body bytes.Buffer
+}
+
+func (recorder *idempotencyRecorder) Write(b []byte) (int, error) {
+	recorder.Body.Write(b)
+
+	return recorder.ResponseWriter.Write(b)
+}
+
+// Idempotency must come after the authentication middlewares, since keys are scoped to the identity of the caller.
+func (cm *CustomMiddleware) Idempotency(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var (
+			tag    string          = "Applications.Routes.Middlewares.Idempotency.Idempotency."
+			cfg    *configs.Config = cm.Application.ConfigHolder.Get()
+			key    string          = c.Request().Header.Get("Idempotency-Key")
+			method string          = c.Request().Method
+		)
+
+		if !cm.Config.UseIdempotency || key == "" || (method != http.MethodPost && method != http.MethodPut && method != http.MethodPatch && method != http.MethodDelete) {
+			return next(c)
+		}
+
+		if len(key) > 255 {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag": tag + "01",
+			}).Error("idempotency key is too long")
+
+			return c.JSON(http.StatusBadRequest, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusBadRequest),
+				Description: "INVALID_IDEMPOTENCY_KEY",
+			})
+		}
+
+		bodyBytes, err := io.ReadAll(c.Request().Body)
+
+		if err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "02",
+				"error": err.Error(),
+			}).Error("failed to read all from request body")
+
+			return c.JSON(http.StatusBadRequest, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusBadRequest),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusBadRequest), " ", "_")),
+			})
+		}
+
+		c.Request().Body.Close()
+
+		c.Request().Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+
+		keySum := sha256.Sum256([]byte(idempotencyClient(c) + "\n" + key))
+		requestSum := sha256.Sum256([]byte(method + "\n" + c.Request().URL.EscapedPath() + "\n" + c.Request().URL.RawQuery + "\n" + string(bodyBytes)))
+
+		record := &models.IdempotencyKey{
+			KeyHash:     hex.EncodeToString(keySum[:]),
+			CreatedAt:   time.Now(),
+			RequestHash: hex.EncodeToString(requestSum[:]),
+			ExpiresAt:   time.Now().Add(time.Second * time.Duration(cfg.IdempotencyLockTimeout)),
+		}
+
+		deadline := time.Now().Add(time.Second * time.Duration(cfg.IdempotencyLockTimeout))
+
+		// A duplicate waits for the request holding the lock, and gets its response once it is stored.
+		for {
+			existing, err := cm.Application.IdempotencyStore.Reserve(c.Request().Context(), record)
+
+			if err != nil {
+				logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+					"tag":   tag + "03",
+					"error": err.Error(),
+				}).Error("failed to reserve idempotency key")
+
+				return c.JSON(http.StatusInternalServerError, types.MainResponse{
+					Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+					Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+				})
+			}
+
+			if existing == nil {
+				break
+			}
+
+			if existing.RequestHash != record.RequestHash {
+				logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+					"tag": tag + "04",
+				}).Error("idempotency key is reused with a different request")
+
+				return c.JSON(http.StatusUnprocessableEntity, types.MainResponse{
+					Code:        fmt.Sprintf("%04d", http.StatusUnprocessableEntity),
+					Description: "IDEMPOTENCY_KEY_REUSED",
+				})
+			}
+
+			if existing.StatusCode != 0 {
+				return replayIdempotentResponse(c, existing)
+			}
+
+			if time.Now().After(deadline) {
+				logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+					"tag": tag + "05",
+				}).Error("idempotency key is still in progress")
+
+				return c.JSON(http.StatusConflict, types.MainResponse{
+					Code:        fmt.Sprintf("%04d", http.StatusConflict),
+					Description: "IDEMPOTENCY_KEY_IN_PROGRESS",
+				})
+			}
+
+			select {
+			case <-c.Request().Context().Done():
+				return c.Request().Context().Err()
+			case <-time.After(time.Millisecond * 100):
+			}
+		}
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Response().Writer}
+
+		c.Response().Writer = recorder
+
+		stopExtend := cm.extendIdempotencyLock(c, record.KeyHash, time.Second*time.Duration(cfg.IdempotencyLockTimeout))
+
+		if err := next(c); err != nil {
+			c.Error(err)
+		}
+
+		stopExtend()
+
+		c.Response().Writer = recorder.ResponseWriter
+
+		// Server errors are not stored, so the request can be retried with the same key.
+		if c.Response().Status >= http.StatusInternalServerError {
+			if err := cm.Application.IdempotencyStore.Release(c.Request().Context(), record.KeyHash); err != nil {
+				logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+					"tag":   tag + "06",
+					"error": err.Error(),
+				}).Error("failed to release idempotency key")
+			}
+
+			return nil
+		}
+
+		header := http.Header{}
+
+		for name, values := range c.Response().Header() {
+			if _, ok := idempotencySkipHeaders[name]; !ok {
+				header[name] = values
+			}
+		}
+
+		headerBytes, _ := json.Marshal(header)
+
+		record.StatusCode = c.Response().Status
+		record.Header = string(headerBytes)
+		record.Body = recorder.Body.Bytes()
+		record.ExpiresAt = time.Now().Add(time.Second * time.Duration(cfg.IdempotencyTTL))
+
+		if err := cm.Application.IdempotencyStore.Save(c.Request().Context(), record); err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "07",
+				"error": err.Error(),
+			}).Error("failed to save idempotency key")
+		}
+
+		return nil
+	}
+}
+
+// The lock is extended every half of its timeout while the request is in progress, so a duplicate never runs the handler at the same time.
+// The returned function stops the extension and waits for it, so it cannot extend the record after it is saved or released.
+func (cm *CustomMiddleware) extendIdempotencyLock(c echo.Context, keyHash string, lockTimeout time.Duration) func() {
+	var (
+		tag  string        = "Applications.Routes.Middlewares.Idempotency.ExtendIdempotencyLock."
+		stop chan struct{} = make(chan struct{})
+		done chan struct{} = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(lockTimeout / 2)
+
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := cm.Application.IdempotencyStore.Extend(context.Background(), keyHash, time.Now().Add(lockTimeout)); err != nil {
+					logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+						"tag":   tag + "01",
+						"error": err.Error(),
+					}).Error("failed to extend idempotency key")
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+
+		<-done
+	}
+}
+
+func replayIdempotentResponse(c echo.Context, record *models.IdempotencyKey) error {
+	var header http.Header
+
+	if err := json.Unmarshal([]byte(record.Header), &header); err == nil {
+		for name, values := range header {
+			c.Response().Header()[name] = values
+		}
+	}
+
+	c.Response().Header().Set("Idempotent-Replayed", "true")
+
+	c.Response().WriteHeader(record.StatusCode)
+
+	_, err := c.Response().Write(record.Body)
+
+	return err
+}
+
+// The subject is the service key ID or the JWT subject, since names are not unique and a response must never be replayed to another caller.
+func idempotencyClient(c echo.Context) string {
+	if identity, ok := c.Get("Identity").(*types.Identity); ok && identity.Subject != "" {
+		return identity.Type + ":" + identity.Subject
+	}
+
+	return "ip:" + c.RealIP()
+}
@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MrAndreID/goechoms/applications"
+	"github.com/MrAndreID/goechoms/applications/types"
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestIdempotencyKeepsIdentitiesWithTheSameNameApart(t *testing.T) {
+	cfg := &configs.Config{
+		UseIdempotency:         true,
+		IdempotencyTTL:         60,
+		IdempotencyLockTimeout: 10,
+	}
+
+	cm := NewCustomMiddleware(cfg, &applications.Application{
+		ConfigHolder:     configs.NewHolder(cfg),
+		IdempotencyStore: applications.NewMemoryIdempotencyStore(),
+	})
+
+	e := echo.New()
+
+	handler := cm.Idempotency(func(c echo.Context) error {
+		return c.String(http.StatusCreated, c.Get("Identity").(*types.Identity).Subject)
+	})
+
+	for _, subject := range []string{"first", "second"} {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/user", bytes.NewBufferString(`{"name":"User"}`))
+
+		request.Header.Set("Idempotency-Key", "same-key")
+
+		recorder := httptest.NewRecorder()
+
+		c := e.NewContext(request, recorder)
+
+		c.Set("Identity", &types.Identity{Type: "service-key", Subject: subject, Name: "same-name"})
+
+		if err := handler(c); err != nil {
+			t.Fatal(err)
+		}
+
+		if recorder.Header().Get("Idempotent-Replayed") != "" {
+			t.Fatalf("expected the response of %s not to be replayed", subject)
+		}
+
+		if recorder.Body.String() != subject {
+			t.Fatalf("expected the response of %s, got %q", subject, recorder.Body.String())
+		}
+	}
+}
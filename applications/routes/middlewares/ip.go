@@ -0,0 +1,99 @@
+package middlewares
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/MrAndreID/goechoms/applications"
+	"github.com/MrAndreID/goechoms/applications/types"
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+func (cm *CustomMiddleware) IPFilter(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var (
+			tag       string          = "Applications.Routes.Middlewares.IP.IPFilter."
+			cfg       *configs.Config = cm.Application.ConfigHolder.Get()
+			routeName string          = cm.RouteName(c)
+			ip        net.IP          = net.ParseIP(c.RealIP())
+		)
+
+		rules, err := cm.Application.IPRules(cfg)
+
+		if err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "01",
+				"error": err.Error(),
+			}).Error("failed to parse ip rules")
+
+			return c.JSON(http.StatusInternalServerError, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+			})
+		}
+
+		if !isIPAllowed(rules, routeName, ip) {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "02",
+				"ip":    c.RealIP(),
+				"route": routeName,
+			}).Warn("ip address is not allowed")
+
+			return c.JSON(http.StatusForbidden, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusForbidden),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusForbidden), " ", "_")),
+			})
+		}
+
+		return next(c)
+	}
+}
+
+// A deny list always wins. An allow list, once set for the route (or globally), must contain the address.
+func isIPAllowed(rules *applications.IPRules, routeName string, ip net.IP) bool {
+	contains := func(networks []*net.IPNet) bool {
+		for _, network := range networks {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	allowList := rules.AllowList
+	denyList := append([]*net.IPNet{}, rules.DenyList...)
+
+	for _, rule := range rules.RouteDenyRules {
+		if strings.HasPrefix(routeName, rule.RoutePrefix) {
+			denyList = append(denyList, rule.Network)
+		}
+	}
+
+	var routeAllowList []*net.IPNet
+
+	for _, rule := range rules.RouteAllowRules {
+		if strings.HasPrefix(routeName, rule.RoutePrefix) {
+			routeAllowList = append(routeAllowList, rule.Network)
+		}
+	}
+
+	if contains(denyList) {
+		return false
+	}
+
+	if len(allowList) > 0 && !contains(allowList) {
+		return false
+	}
+
+	if len(routeAllowList) > 0 && !contains(routeAllowList) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,154 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/MrAndreID/goechoms/applications"
+	"github.com/MrAndreID/goechoms/applications/types"
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+)
+
+func (cm *CustomMiddleware) JWTCheck(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var (
+			tag           string = "Applications.Routes.Middlewares.JWT.JWTCheck."
+			authorization string = c.Request().Header.Get(echo.HeaderAuthorization)
+		)
+
+		scheme, tokenString, ok := strings.Cut(authorization, " ")
+
+		if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(tokenString) == "" {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag": tag + "01",
+			}).Error("failed to checking bearer token from header")
+
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="`+cm.Config.AppName+`"`)
+
+			return c.JSON(http.StatusUnauthorized, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusUnauthorized),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusUnauthorized), " ", "_")),
+			})
+		}
+
+		claims, err := cm.Application.ParseJWT(cm.Config, strings.TrimSpace(tokenString))
+
+		if err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "02",
+				"error": err.Error(),
+			}).Error("failed to validate bearer token")
+
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="`+cm.Config.AppName+`", error="invalid_token"`)
+
+			return c.JSON(http.StatusUnauthorized, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusUnauthorized),
+				Description: "INVALID_TOKEN",
+			})
+		}
+
+		subject, _ := claims.GetSubject()
+
+		identity := &types.Identity{
+			Type:    "jwt",
+			Subject: subject,
+			Name:    cast.ToString(claims["name"]),
+			Scopes:  applications.ClaimStrings(claims, cm.Config.JWTScopeClaim),
+			Roles:   applications.ClaimStrings(claims, cm.Config.JWTRoleClaim),
+		}
+
+		if identity.Name == "" {
+			identity.Name = subject
+		}
+
+		c.Set("Identity", identity)
+		c.Set("JWTClaims", claims)
+
+		c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), configs.ClientKey, identity.Name)))
+
+		return next(c)
+	}
+}
+
+// Callers presenting a bearer token are authenticated with JWTCheck, everyone else with ServiceKeyCheck.
+func (cm *CustomMiddleware) ServiceKeyOrJWTCheck(next echo.HandlerFunc) echo.HandlerFunc {
+	jwtCheck := cm.JWTCheck(next)
+	serviceKeyCheck := cm.ServiceKeyCheck(next)
+
+	return func(c echo.Context) error {
+		if cm.Config.UseJWT && c.Request().Header.Get(echo.HeaderAuthorization) != "" && c.Request().Header.Get("Service-Key") == "" {
+			return jwtCheck(c)
+		}
+
+		return serviceKeyCheck(c)
+	}
+}
+
+// Scopes and roles only apply to JWT identities, service keys are already restricted to their route names.
+func (cm *CustomMiddleware) RequireScopes(scopes ...string) echo.MiddlewareFunc {
+	return cm.requireClaims("scopes", scopes, func(identity *types.Identity, value string) bool {
+		return identity.HasScope(value)
+	})
+}
+
+func (cm *CustomMiddleware) RequireRoles(roles ...string) echo.MiddlewareFunc {
+	return cm.requireClaims("roles", roles, func(identity *types.Identity, value string) bool {
+		return identity.HasRole(value)
+	})
+}
+
+func (cm *CustomMiddleware) requireClaims(name string, values []string, has func(identity *types.Identity, value string) bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var tag string = "Applications.Routes.Middlewares.JWT.RequireClaims."
+
+			identity, ok := c.Get("Identity").(*types.Identity)
+
+			if !ok {
+				logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+					"tag": tag + "01",
+				}).Error("identity not found")
+
+				return c.JSON(http.StatusUnauthorized, types.MainResponse{
+					Code:        fmt.Sprintf("%04d", http.StatusUnauthorized),
+					Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusUnauthorized), " ", "_")),
+				})
+			}
+
+			if identity.Type != "jwt" {
+				return next(c)
+			}
+
+			var missing []string
+
+			for _, value := range values {
+				if !has(identity, value) {
+					missing = append(missing, value)
+				}
+			}
+
+			if len(missing) > 0 {
+				logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+					"tag":   tag + "02",
+					"error": "Missing " + name + ": " + strings.Join(missing, ", "),
+				}).Error("insufficient " + name)
+
+				return c.JSON(http.StatusForbidden, types.MainResponse{
+					Code:        fmt.Sprintf("%04d", http.StatusForbidden),
+					Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusForbidden), " ", "_")),
+					Data: map[string][]string{
+						name: missing,
+					},
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (cm *CustomMiddleware) Metrics(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var (
+			method    string    = c.Request().Method
+			routeName string    = cm.RouteName(c)
+			startedAt time.Time = time.Now()
+		)
+
+		if routeName == "" {
+			routeName = "unknown"
+		}
+
+		inFlight := cm.Application.Metrics.RequestsInFlight.WithLabelValues(routeName)
+
+		inFlight.Inc()
+
+		defer inFlight.Dec()
+
+		err := next(c)
+
+		if err != nil {
+			c.Error(err)
+		}
+
+		statusCode := strconv.Itoa(c.Response().Status)
+
+		cm.Application.Metrics.RequestsTotal.WithLabelValues(routeName, method, statusCode).Inc()
+		cm.Application.Metrics.RequestDuration.WithLabelValues(routeName, method, statusCode).Observe(time.Since(startedAt).Seconds())
+
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/MrAndreID/goechoms/applications"
+	"github.com/MrAndreID/goechoms/applications/types"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// PermissionCheck must come after the authentication middlewares, the permissions are taken from the roles of the identity.
+func (cm *CustomMiddleware) PermissionCheck(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var tag string = "Applications.Routes.Middlewares.Permission.PermissionCheck."
+
+		if !cm.Config.UsePermission {
+			return next(c)
+		}
+
+		rules, err := cm.Application.PermissionRules(cm.Application.ConfigHolder.Get())
+
+		if err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "01",
+				"error": err.Error(),
+			}).Error("failed to parse permission rules")
+
+			return c.JSON(http.StatusInternalServerError, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+			})
+		}
+
+		return cm.checkPermissions(c, next, rules.Route(cm.RouteName(c)))
+	}
+}
+
+// RequirePermissions is checked whether USE_PERMISSION is enabled or not, for routes that must never be open to every caller.
+func (cm *CustomMiddleware) RequirePermissions(permissions ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return cm.checkPermissions(c, next, permissions)
+		}
+	}
+}
+
+func (cm *CustomMiddleware) checkPermissions(c echo.Context, next echo.HandlerFunc, required []string) error {
+	var tag string = "Applications.Routes.Middlewares.Permission.CheckPermissions."
+
+	if len(required) == 0 {
+		return next(c)
+	}
+
+	identity, ok := c.Get("Identity").(*types.Identity)
+
+	if !ok {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag": tag + "01",
+		}).Error("identity not found")
+
+		return c.JSON(http.StatusUnauthorized, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusUnauthorized),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusUnauthorized), " ", "_")),
+		})
+	}
+
+	permissions, err := cm.Application.IdentityPermissions(c.Request().Context(), identity)
+
+	if err != nil {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "02",
+			"error": err.Error(),
+		}).Error("failed to get permissions")
+
+		return c.JSON(http.StatusInternalServerError, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+		})
+	}
+
+	var missing []string
+
+	for _, permission := range required {
+		if !applications.HasPermission(permissions, permission) {
+			missing = append(missing, permission)
+		}
+	}
+
+	if len(missing) > 0 {
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   tag + "03",
+			"route": cm.RouteName(c),
+			"error": "Missing permissions: " + strings.Join(missing, ", "),
+		}).Error("insufficient permissions")
+
+		return c.JSON(http.StatusForbidden, types.MainResponse{
+			Code:        fmt.Sprintf("%04d", http.StatusForbidden),
+			Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusForbidden), " ", "_")),
+			Data: map[string][]string{
+				"permissions": missing,
+			},
+		})
+	}
+
+	return next(c)
+}
@@ -0,0 +1,154 @@
+package middlewares
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MrAndreID/goechoms/applications/types"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// IPRateLimit limits every client by its IP address with RATE_LIMIT_IP, and must come before the authentication middlewares, so floods of unauthenticated requests are limited too.
+func (cm *CustomMiddleware) IPRateLimit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var tag string = "Applications.Routes.Middlewares.RateLimit.IPRateLimit."
+
+		if !cm.Config.UseRateLimit {
+			return next(c)
+		}
+
+		rateLimits, err := cm.Application.RateLimits(cm.Application.ConfigHolder.Get())
+
+		if err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "01",
+				"error": err.Error(),
+			}).Error("failed to parse rate limits")
+
+			cm.Application.Metrics.ObserveError("rate_limit")
+
+			return next(c)
+		}
+
+		if rateLimits.IP == nil {
+			return next(c)
+		}
+
+		result, err := cm.Application.CheckRateLimit(c.Request().Context(), "ip:"+c.RealIP(), *rateLimits.IP)
+
+		if err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "02",
+				"error": err.Error(),
+			}).Error("failed to check rate limit")
+
+			cm.Application.Metrics.ObserveError("rate_limit")
+
+			return next(c)
+		}
+
+		if !result.Allowed {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag": tag + "03",
+				"ip":  c.RealIP(),
+			}).Warn("rate limit exceeded")
+
+			c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(result.Reset.Seconds()))))
+
+			return c.JSON(http.StatusTooManyRequests, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusTooManyRequests),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusTooManyRequests), " ", "_")),
+			})
+		}
+
+		return next(c)
+	}
+}
+
+// RateLimit must come after the authentication middlewares, so the identity is known when RATE_LIMIT_KEY=identity.
+func (cm *CustomMiddleware) RateLimit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var (
+			tag       string = "Applications.Routes.Middlewares.RateLimit.RateLimit."
+			routeName string = cm.RouteName(c)
+		)
+
+		if !cm.Config.UseRateLimit {
+			return next(c)
+		}
+
+		rateLimits, err := cm.Application.RateLimits(cm.Application.ConfigHolder.Get())
+
+		if err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "01",
+				"error": err.Error(),
+			}).Error("failed to parse rate limits")
+
+			cm.Application.Metrics.ObserveError("rate_limit")
+
+			return next(c)
+		}
+
+		rateLimit, ok := rateLimits.Route(routeName)
+
+		if !ok {
+			return next(c)
+		}
+
+		result, err := cm.Application.CheckRateLimit(c.Request().Context(), routeName+":"+cm.rateLimitClient(c), rateLimit)
+
+		if err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "02",
+				"error": err.Error(),
+			}).Error("failed to check rate limit")
+
+			cm.Application.Metrics.ObserveError("rate_limit")
+
+			return next(c)
+		}
+
+		c.Response().Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Response().Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Response().Header().Set("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.Reset.Seconds()))))
+
+		if !result.Allowed {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "03",
+				"route": routeName,
+			}).Warn("rate limit exceeded")
+
+			c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(result.Reset.Seconds()))))
+
+			return c.JSON(http.StatusTooManyRequests, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusTooManyRequests),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusTooManyRequests), " ", "_")),
+			})
+		}
+
+		return next(c)
+	}
+}
+
+// Callers without an identity, or without the configured header, are limited by their IP address.
+// Identities are limited by their subject, since names are not unique and must not share a limit.
+func (cm *CustomMiddleware) rateLimitClient(c echo.Context) string {
+	switch cm.Config.RateLimitKey {
+	case "identity":
+		if identity, ok := c.Get("Identity").(*types.Identity); ok && identity.Subject != "" {
+			return identity.Type + ":" + identity.Subject
+		}
+	case "header":
+		if value := c.Request().Header.Get(cm.Config.RateLimitHeader); value != "" {
+			return "header:" + value
+		}
+	}
+
+	return "ip:" + c.RealIP()
+}
@@ -1,25 +1,59 @@
 package middlewares
 
 import (
+	"context"
+	"regexp"
+
+	"github.com/MrAndreID/goechoms/configs"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 )
 
+var requestIDPattern *regexp.Regexp = regexp.MustCompile(`^[A-Za-z0-9\-_.:]+$`)
+
 func (cm *CustomMiddleware) SetRequestID(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		UUID, err := uuid.NewRandom()
+		var (
+			tag       string = "Applications.Routes.Middlewares.RequestID.SetRequestID."
+			requestID string = c.Request().Header.Get(echo.HeaderXRequestID)
+		)
 
-		if err != nil {
-			logrus.WithFields(logrus.Fields{
-				"tag":   "Applications.Routes.Middlewares.RequestID.SetRequestID.01",
-				"error": err.Error(),
-			}).Error("failed to generate uuid for request id")
+		if requestID != "" {
+			err := validation.Validate(requestID, validation.Length(1, 128), validation.Match(requestIDPattern))
 
-			return err
+			if !cm.Config.TrustRequestID || err != nil {
+				logrus.WithFields(logrus.Fields{
+					"tag":       tag + "01",
+					"requestId": requestID,
+				}).Warn("ignoring inbound request id")
+
+				requestID = ""
+			}
 		}
 
-		c.Set("RequestID", &UUID)
+		if requestID == "" {
+			UUID, err := uuid.NewRandom()
+
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"tag":   tag + "02",
+					"error": err.Error(),
+				}).Error("failed to generate uuid for request id")
+
+				return err
+			}
+
+			requestID = UUID.String()
+		}
+
+		c.Set("RequestID", requestID)
+
+		c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), configs.RequestIDKey, requestID)))
+
+		c.Response().Header().Set(echo.HeaderXRequestID, requestID)
 
 		return next(c)
 	}
@@ -12,3 +12,12 @@ func (cm *CustomMiddleware) NoCache(next echo.HandlerFunc) echo.HandlerFunc {
 		return next(c)
 	}
 }
+
+// JSON responses are signed by CustomJSON.Serialize, since the body is only known once it is serialized.
+func (cm *CustomMiddleware) SignResponse(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Set("SignResponse", true)
+
+		return next(c)
+	}
+}
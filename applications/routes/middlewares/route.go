@@ -1,6 +1,8 @@
 package middlewares
 
 import (
+	"strings"
+
 	"github.com/labstack/echo/v4"
 )
 
@@ -21,3 +23,22 @@ func (cm *CustomMiddleware) SetRouteList(routes []*echo.Route) map[string]map[st
 
 	return routeList
 }
+
+func (cm *CustomMiddleware) RouteName(c echo.Context) string {
+	return cm.RouteList[c.Path()][c.Request().Method]
+}
+
+// SetRouteName keeps the route name for the handlers, such as the audit log, which cannot read the route list.
+func (cm *CustomMiddleware) SetRouteName(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Set("RouteName", cm.RouteName(c))
+
+		return next(c)
+	}
+}
+
+func (cm *CustomMiddleware) IsPublicRoute(c echo.Context) bool {
+	routeName := cm.RouteName(c)
+
+	return routeName == "metrics" || strings.HasPrefix(routeName, "health.")
+}
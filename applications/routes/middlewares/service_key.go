@@ -1,11 +1,15 @@
 package middlewares
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/MrAndreID/goechoms/applications"
 	"github.com/MrAndreID/goechoms/applications/types"
+	"github.com/MrAndreID/goechoms/configs"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/labstack/echo/v4"
@@ -17,7 +21,7 @@ func (cm *CustomMiddleware) ServiceKeyCheck(next echo.HandlerFunc) echo.HandlerF
 		var tag string = "Applications.Routes.Middlewares.ServiceKey.ServiceKeyCheck."
 
 		if c.Request().Header["Service-Key"] == nil || c.Request().Header["Service-Key"][0] == "" {
-			logrus.WithFields(logrus.Fields{
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 				"tag": tag + "01",
 			}).Error("failed to checking service key from header")
 
@@ -30,7 +34,7 @@ func (cm *CustomMiddleware) ServiceKeyCheck(next echo.HandlerFunc) echo.HandlerF
 		err := validation.Validate(c.Request().Header["Service-Key"][0], validation.Required, validation.By(types.BlacklistValidation("Service-Key")))
 
 		if err != nil {
-			logrus.WithFields(logrus.Fields{
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 				"tag":   tag + "02",
 				"error": err.Error(),
 			}).Error("failed to checking service key from header")
@@ -44,17 +48,55 @@ func (cm *CustomMiddleware) ServiceKeyCheck(next echo.HandlerFunc) echo.HandlerF
 			})
 		}
 
-		if c.Request().Header["Service-Key"][0] != cm.Config.ServiceKey {
-			logrus.WithFields(logrus.Fields{
-				"tag": tag + "03",
+		serviceKey, err := cm.Application.FindServiceKey(c.Request().Context(), c.Request().Header["Service-Key"][0])
+
+		if err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "03",
+				"error": err.Error(),
 			}).Error("failed to checking service key from header")
 
+			if !errors.Is(err, applications.ErrServiceKeyNotFound) && !errors.Is(err, applications.ErrServiceKeyExpired) && !errors.Is(err, applications.ErrServiceKeyDisabled) {
+				return c.JSON(http.StatusInternalServerError, types.MainResponse{
+					Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+					Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+				})
+			}
+
 			return c.JSON(http.StatusBadRequest, types.MainResponse{
 				Code:        fmt.Sprintf("%04d", http.StatusBadRequest),
 				Description: "INVALID_SERVICE_KEY",
 			})
 		}
 
+		identity := &types.Identity{
+			Type:    "service-key",
+			Subject: serviceKey.ID,
+			Name:    serviceKey.Name,
+			Scopes:  applications.ServiceKeyRoutes(serviceKey),
+			Roles:   applications.ServiceKeyRoles(serviceKey),
+		}
+
+		if identity.Subject == "" {
+			identity.Subject = serviceKey.Name
+		}
+
+		c.Set("Identity", identity)
+
+		c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), configs.ClientKey, identity.Name)))
+
+		if !identity.HasScope(cm.RouteName(c)) {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "04",
+				"route": cm.RouteName(c),
+			}).Error("service key is not allowed to access route")
+
+			return c.JSON(http.StatusForbidden, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusForbidden),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusForbidden), " ", "_")),
+			})
+		}
+
 		return next(c)
 	}
 }
@@ -2,12 +2,16 @@ package middlewares
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 
+	"github.com/MrAndreID/goechoms/applications"
 	"github.com/MrAndreID/goechoms/applications/types"
+	"github.com/MrAndreID/goechoms/signatures"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
@@ -22,7 +26,8 @@ func (cm *CustomMiddleware) SignatureCheck(next echo.HandlerFunc) echo.HandlerFu
 			contentType            string = c.Request().Header.Get("Content-Type")
 			signature              string = c.Request().Header.Get(cm.Config.SignatureName)
 			signatureTransactionID string = c.Request().Header.Get(cm.Config.SignatureTransactionIDName)
-			url, stringToSign      string
+			signatureKeyID         string = c.Request().Header.Get(cm.Config.SignatureKeyIDName)
+			signatureVersion       string = c.Request().Header.Get(cm.Config.SignatureVersionName)
 		)
 
 		methodToSkip := map[string]struct{}{
@@ -36,10 +41,14 @@ func (cm *CustomMiddleware) SignatureCheck(next echo.HandlerFunc) echo.HandlerFu
 			return next(c)
 		}
 
+		if cm.IsPublicRoute(c) {
+			return next(c)
+		}
+
 		c.Response().Header().Add(cm.Config.SignatureValidationName, "FALSE")
 
 		if signature == "" || signatureTransactionID == "" {
-			logrus.WithFields(logrus.Fields{
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 				"tag":   tag + "01",
 				"error": "Failed to Check Signature & Signature Transaction ID",
 			}).Error("failed to check signature & signature transaction id")
@@ -50,15 +59,28 @@ func (cm *CustomMiddleware) SignatureCheck(next echo.HandlerFunc) echo.HandlerFu
 			})
 		}
 
-		if c.Request().URL.RawQuery != "" {
-			url = "?" + c.Request().URL.RawQuery
+		if signatureVersion == "" {
+			signatureVersion = signatures.Version1
+		}
+
+		if !slices.Contains(cm.Application.ConfigHolder.Get().SignatureVersions, signatureVersion) {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":     tag + "02",
+				"version": signatureVersion,
+				"error":   signatures.ErrVersionNotFound.Error(),
+			}).Error("signature version is not supported")
+
+			return c.JSON(http.StatusUnauthorized, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusUnauthorized),
+				Description: "UNSUPPORTED_SIGNATURE_VERSION",
+			})
 		}
 
 		bodyBytes, err := io.ReadAll(c.Request().Body)
 
 		if err != nil {
-			logrus.WithFields(logrus.Fields{
-				"tag":   tag + "02",
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "03",
 				"error": err.Error(),
 			}).Error("failed to read all from request body")
 
@@ -72,17 +94,11 @@ func (cm *CustomMiddleware) SignatureCheck(next echo.HandlerFunc) echo.HandlerFu
 
 		c.Request().Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 
-		bodyString := string(bodyBytes)
-
-		if bodyString == "" {
-			bodyString = "{}"
-		}
-
-		transactionId, err := cm.Application.DecryptSignature(cm.Config, signatureTransactionID)
+		transactionId, err := cm.Application.DecryptSignature(cm.Application.ConfigHolder.Get(), signatureTransactionID)
 
 		if err != nil {
-			logrus.WithFields(logrus.Fields{
-				"tag":   tag + "03",
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "04",
 				"error": err.Error(),
 			}).Error("failed to decrypt signature for transaction id")
 
@@ -93,8 +109,8 @@ func (cm *CustomMiddleware) SignatureCheck(next echo.HandlerFunc) echo.HandlerFu
 		}
 
 		if transactionId == nil {
-			logrus.WithFields(logrus.Fields{
-				"tag":   tag + "04",
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "05",
 				"error": "Transaction ID is Null",
 			}).Error("transaction id is null")
 
@@ -104,21 +120,13 @@ func (cm *CustomMiddleware) SignatureCheck(next echo.HandlerFunc) echo.HandlerFu
 			})
 		}
 
-		if method == "GET" || contentType == "multipart/form-data" {
-			requestBody := "{}"
-
-			stringToSign = url + "|" + requestBody + "|" + cast.ToString(transactionId)
-		} else {
-			stringToSign = url + "|" + bodyString + "|" + cast.ToString(transactionId)
-		}
-
-		verifySignature, err := cm.Application.VerifySignature(cm.Config, stringToSign)
+		stringToSign, err := signatures.StringToSign(signatureVersion, method, c.Request().URL.EscapedPath(), c.Request().URL.RawQuery, contentType, bodyBytes, cast.ToString(transactionId))
 
 		if err != nil {
-			logrus.WithFields(logrus.Fields{
-				"tag":   tag + "05",
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "06",
 				"error": err.Error(),
-			}).Error("failed to verify signature")
+			}).Error("failed to build string to sign")
 
 			return c.JSON(http.StatusUnauthorized, types.MainResponse{
 				Code:        fmt.Sprintf("%04d", http.StatusUnauthorized),
@@ -126,11 +134,12 @@ func (cm *CustomMiddleware) SignatureCheck(next echo.HandlerFunc) echo.HandlerFu
 			})
 		}
 
-		if verifySignature == nil || cast.ToString(verifySignature) != signature {
-			logrus.WithFields(logrus.Fields{
-				"tag":   tag + "06",
-				"error": "Verify Signature is Null or Verify Signature is Not Equal with Signature",
-			}).Error("verify signature is null or verify signature is not equal with signature")
+		if err := cm.Application.VerifySignature(cm.Application.ConfigHolder.Get(), signatureKeyID, stringToSign, signature); err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "07",
+				"kid":   signatureKeyID,
+				"error": err.Error(),
+			}).Error("failed to verify signature")
 
 			return c.JSON(http.StatusUnauthorized, types.MainResponse{
 				Code:        fmt.Sprintf("%04d", http.StatusUnauthorized),
@@ -138,6 +147,31 @@ func (cm *CustomMiddleware) SignatureCheck(next echo.HandlerFunc) echo.HandlerFu
 			})
 		}
 
+		if err := cm.Application.CheckTransactionID(c.Request().Context(), cast.ToString(transactionId)); err != nil {
+			logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+				"tag":   tag + "08",
+				"error": err.Error(),
+			}).Error("failed to check transaction id")
+
+			switch {
+			case errors.Is(err, applications.ErrTransactionIDReplayed):
+				return c.JSON(http.StatusConflict, types.MainResponse{
+					Code:        fmt.Sprintf("%04d", http.StatusConflict),
+					Description: "REPLAYED_TRANSACTION_ID",
+				})
+			case errors.Is(err, applications.ErrTransactionIDExpired), errors.Is(err, applications.ErrTransactionIDTimestampMissing):
+				return c.JSON(http.StatusUnauthorized, types.MainResponse{
+					Code:        fmt.Sprintf("%04d", http.StatusUnauthorized),
+					Description: "EXPIRED_TRANSACTION_ID",
+				})
+			}
+
+			return c.JSON(http.StatusInternalServerError, types.MainResponse{
+				Code:        fmt.Sprintf("%04d", http.StatusInternalServerError),
+				Description: strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")),
+			})
+		}
+
 		c.Response().Header().Set(cm.Config.SignatureValidationName, "TRUE")
 
 		return next(c)
@@ -0,0 +1,203 @@
+package applications
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/MrAndreID/goechoms/applications/databases/models"
+	"github.com/MrAndreID/goechoms/configs"
+
+	redisPackage "github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrServiceKeyNotFound = errors.New("Service Key Not Found")
+	ErrServiceKeyExpired  = errors.New("Service Key Expired")
+	ErrServiceKeyDisabled = errors.New("Service Key Disabled")
+)
+
+func HashServiceKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func ServiceKeyRoutes(serviceKey *models.ServiceKey) []string {
+	return splitServiceKeyList(serviceKey.Routes)
+}
+
+func ServiceKeyRoles(serviceKey *models.ServiceKey) []string {
+	return splitServiceKeyList(serviceKey.Roles)
+}
+
+func splitServiceKeyList(value string) []string {
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func (app *Application) FindServiceKey(ctx context.Context, key string) (*models.ServiceKey, error) {
+	var (
+		cfg        *configs.Config = app.ConfigHolder.Get()
+		keyHash    string          = HashServiceKey(key)
+		serviceKey *models.ServiceKey
+		err        error
+	)
+
+	if cfg.ServiceKeyStore == "database" {
+		serviceKey, err = app.findDatabaseServiceKey(ctx, cfg, keyHash)
+	} else {
+		serviceKey, err = app.findConfigServiceKey(cfg, keyHash)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !serviceKey.Enabled {
+		return nil, ErrServiceKeyDisabled
+	}
+
+	if serviceKey.ExpiresAt != nil && time.Now().After(*serviceKey.ExpiresAt) {
+		return nil, ErrServiceKeyExpired
+	}
+
+	return serviceKey, nil
+}
+
+// During a rotation both SERVICE_KEY and SERVICE_KEY_PREVIOUS are accepted until SERVICE_KEY_PREVIOUS_EXPIRES_AT.
+func (app *Application) findConfigServiceKey(cfg *configs.Config, keyHash string) (*models.ServiceKey, error) {
+	candidates := []models.ServiceKey{
+		{
+			Name:    "default",
+			KeyHash: HashServiceKey(cfg.ServiceKey),
+			Routes:  "*",
+			Roles:   strings.Join(cfg.ServiceKeyRoles, ","),
+			Enabled: cfg.ServiceKey != "",
+		},
+		{
+			Name:    "default-previous",
+			KeyHash: HashServiceKey(cfg.ServiceKeyPrevious),
+			Routes:  "*",
+			Roles:   strings.Join(cfg.ServiceKeyRoles, ","),
+			Enabled: cfg.ServiceKeyPrevious != "",
+		},
+	}
+
+	if cfg.ServiceKeyPreviousExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, cfg.ServiceKeyPreviousExpiresAt)
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   "Applications.ServiceKey.FindConfigServiceKey.01",
+				"error": err.Error(),
+			}).Error("failed to parse expiry of previous service key")
+
+			return nil, err
+		}
+
+		candidates[1].ExpiresAt = &expiresAt
+	}
+
+	var found *models.ServiceKey
+
+	for i := range candidates {
+		if subtle.ConstantTimeCompare([]byte(candidates[i].KeyHash), []byte(keyHash)) == 1 && candidates[i].Enabled && found == nil {
+			found = &candidates[i]
+		}
+	}
+
+	if found == nil {
+		return nil, ErrServiceKeyNotFound
+	}
+
+	return found, nil
+}
+
+func (app *Application) findDatabaseServiceKey(ctx context.Context, cfg *configs.Config, keyHash string) (*models.ServiceKey, error) {
+	var (
+		tag        string        = "Applications.ServiceKey.FindDatabaseServiceKey."
+		cacheKey   string        = serviceKeyCacheKey(keyHash)
+		cacheTTL   time.Duration = time.Second * time.Duration(cfg.ServiceKeyCacheTTL)
+		serviceKey models.ServiceKey
+	)
+
+	if app.Redis != nil && cacheTTL > 0 {
+		cached, err := app.Redis.Get(ctx, cacheKey).Result()
+
+		switch {
+		case err == nil && cached == "":
+			return nil, ErrServiceKeyNotFound
+		case err == nil:
+			if err := json.Unmarshal([]byte(cached), &serviceKey); err == nil {
+				serviceKey.KeyHash = keyHash
+
+				return &serviceKey, nil
+			}
+		case !errors.Is(err, redisPackage.Nil):
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"tag":   tag + "01",
+				"error": err.Error(),
+			}).Error("failed to get service key from redis")
+		}
+	}
+
+	result := app.Database.WithContext(ctx).First(&serviceKey, "key_hash = ?", keyHash)
+
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"tag":   tag + "02",
+			"error": result.Error.Error(),
+		}).Error("failed to get service key from database")
+
+		return nil, result.Error
+	}
+
+	var cached []byte
+
+	if result.Error == nil {
+		cached, _ = json.Marshal(serviceKey)
+	}
+
+	if app.Redis != nil && cacheTTL > 0 {
+		if err := app.Redis.Set(ctx, cacheKey, string(cached), cacheTTL).Err(); err != nil {
+			logrus.WithContext(ctx).WithFields(logrus.Fields{
+				"tag":   tag + "03",
+				"error": err.Error(),
+			}).Error("failed to set service key to redis")
+		}
+	}
+
+	if result.Error != nil {
+		return nil, ErrServiceKeyNotFound
+	}
+
+	return &serviceKey, nil
+}
+
+func serviceKeyCacheKey(keyHash string) string {
+	return "service-key:" + keyHash
+}
+
+// ForgetServiceKey removes the cached record of a service key, so a change of the record applies to the next request.
+func (app *Application) ForgetServiceKey(ctx context.Context, keyHash string) error {
+	if app.Redis == nil {
+		return nil
+	}
+
+	return app.Redis.Del(ctx, serviceKeyCacheKey(keyHash)).Err()
+}
@@ -1,9 +1,12 @@
 package services
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/MrAndreID/goechoms/applications/metrics"
 	"github.com/MrAndreID/goechoms/applications/types"
 	"github.com/MrAndreID/goechoms/configs"
 
@@ -12,28 +15,37 @@ import (
 )
 
 type CurrencyService struct {
-	Config *configs.Config
+	Config       *configs.Config
+	ConfigHolder *configs.Holder
+	Metrics      *metrics.Metrics
 }
 
-func NewCurrencyService(cfg *configs.Config) *CurrencyService {
+func NewCurrencyService(cfg *configs.Config, configHolder *configs.Holder, metricsCollector *metrics.Metrics) *CurrencyService {
 	return &CurrencyService{
-		Config: cfg,
+		Config:       cfg,
+		ConfigHolder: configHolder,
+		Metrics:      metricsCollector,
 	}
 }
 
-func (cs *CurrencyService) Index(httpResponse *types.HTTPResponse) {
+func (cs *CurrencyService) Index(ctx context.Context, httpResponse *types.HTTPResponse) {
 	var (
 		restyClient *resty.Client = resty.New()
 		tag         string        = "Applications.Services.Currency.Index."
 	)
 
-	restyClient.SetTimeout(time.Second * time.Duration(cs.Config.DefaultTimeout))
+	restyClient.SetTimeout(time.Second * time.Duration(cs.ConfigHolder.Get().DefaultTimeout))
 
 	url := cs.Config.CurrencyURL + "/currencies"
 
-	restyResponse, err := restyClient.R().Get(url)
+	startedAt := time.Now()
+
+	restyResponse, err := NewRequest(ctx, restyClient).Get(url)
+
+	cs.Metrics.ObserveOutbound("currency", "index", restyResponse.StatusCode(), time.Since(startedAt))
+
 	if err != nil {
-		logrus.WithFields(logrus.Fields{
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
 			"tag":           tag + "01",
 			"url":           url,
 			"requestHeader": restyResponse.Request.Header,
@@ -51,7 +63,7 @@ func (cs *CurrencyService) Index(httpResponse *types.HTTPResponse) {
 	httpResponse.Body = string(restyResponse.Body())
 	httpResponse.StatusCode = restyResponse.StatusCode()
 
-	logrus.WithFields(logrus.Fields{
+	logrus.WithContext(ctx).WithFields(logrus.Fields{
 		"tag":                tag + "02",
 		"url":                url,
 		"requestHeader":      restyResponse.Request.Header,
@@ -61,3 +73,42 @@ func (cs *CurrencyService) Index(httpResponse *types.HTTPResponse) {
 		"responseStatusCode": restyResponse.StatusCode(),
 	}).Info("result from hit to currency url")
 }
+
+func (cs *CurrencyService) Ping(ctx context.Context) error {
+	var (
+		restyClient *resty.Client = resty.New()
+		tag         string        = "Applications.Services.Currency.Ping."
+	)
+
+	restyClient.SetTimeout(time.Second * time.Duration(cs.ConfigHolder.Get().DefaultTimeout))
+
+	url := cs.Config.CurrencyURL + "/currencies"
+
+	startedAt := time.Now()
+
+	restyResponse, err := NewRequest(ctx, restyClient).Get(url)
+
+	cs.Metrics.ObserveOutbound("currency", "ping", restyResponse.StatusCode(), time.Since(startedAt))
+
+	if err != nil {
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"url":   url,
+			"error": err.Error(),
+		}).Error("failed hit to currency url")
+
+		return err
+	}
+
+	if restyResponse.StatusCode() != http.StatusOK {
+		logrus.WithContext(ctx).WithFields(logrus.Fields{
+			"tag":                tag + "02",
+			"url":                url,
+			"responseStatusCode": restyResponse.StatusCode(),
+		}).Error("unexpected status code from currency url")
+
+		return fmt.Errorf("unexpected status code %d from currency url", restyResponse.StatusCode())
+	}
+
+	return nil
+}
@@ -1,9 +1,14 @@
 package services
 
 import (
+	"context"
+
+	"github.com/MrAndreID/goechoms/applications/metrics"
 	"github.com/MrAndreID/goechoms/configs"
 
 	redisPackage "github.com/go-redis/redis/v8"
+	"github.com/go-resty/resty/v2"
+	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
@@ -11,8 +16,18 @@ type Service struct {
 	Currency *CurrencyService
 }
 
-func New(cfg *configs.Config, redisConnection *redisPackage.Client, databaseConnection *gorm.DB) *Service {
+func New(cfg *configs.Config, configHolder *configs.Holder, redisConnection *redisPackage.Client, databaseConnection *gorm.DB, metricsCollector *metrics.Metrics) *Service {
 	return &Service{
-		Currency: NewCurrencyService(cfg),
+		Currency: NewCurrencyService(cfg, configHolder, metricsCollector),
+	}
+}
+
+func NewRequest(ctx context.Context, restyClient *resty.Client) *resty.Request {
+	request := restyClient.R().SetContext(ctx)
+
+	if requestID, ok := ctx.Value(configs.RequestIDKey).(string); ok && requestID != "" {
+		request.SetHeader(echo.HeaderXRequestID, requestID)
 	}
+
+	return request
 }
@@ -0,0 +1,119 @@
+package applications
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MrAndreID/goechoms/configs"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ExitCodeSuccess       int = 0
+	ExitCodeServerError   int = 1
+	ExitCodeShutdownError int = 2
+)
+
+type Worker struct {
+	Name string
+	Stop func(ctx context.Context) error
+}
+
+// Workers are stopped in reverse order of registration, before the database and redis are closed.
+func (app *Application) RegisterWorker(name string, stop func(ctx context.Context) error) {
+	app.Workers = append(app.Workers, Worker{
+		Name: name,
+		Stop: stop,
+	})
+}
+
+func (app *Application) Shutdown(cfg *configs.Config, e *echo.Echo, reason string) (int, error) {
+	var (
+		tag       string    = "Applications.Shutdown.Shutdown."
+		startedAt time.Time = time.Now()
+		failures  []error
+		stopped   []string
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(cfg.ShutdownTimeout))
+
+	defer cancel()
+
+	if err := e.Shutdown(ctx); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.Error(),
+		}).Error("failed to drain in-flight requests")
+
+		failures = append(failures, err)
+	} else {
+		stopped = append(stopped, "server")
+	}
+
+	for i := len(app.Workers) - 1; i >= 0; i-- {
+		worker := app.Workers[i]
+
+		if err := worker.Stop(ctx); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":    tag + "02",
+				"worker": worker.Name,
+				"error":  err.Error(),
+			}).Error("failed to stop worker")
+
+			failures = append(failures, err)
+
+			continue
+		}
+
+		stopped = append(stopped, worker.Name)
+	}
+
+	if app.Database != nil {
+		sqlDB, err := app.Database.DB()
+
+		if err == nil {
+			err = sqlDB.Close()
+		}
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "03",
+				"error": err.Error(),
+			}).Error("failed to close database")
+
+			failures = append(failures, err)
+		} else {
+			stopped = append(stopped, "database")
+		}
+	}
+
+	if app.Redis != nil {
+		if err := app.Redis.Close(); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "04",
+				"error": err.Error(),
+			}).Error("failed to close redis")
+
+			failures = append(failures, err)
+		} else {
+			stopped = append(stopped, "redis")
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"tag":      tag + "05",
+		"reason":   reason,
+		"duration": time.Since(startedAt).String(),
+		"stopped":  stopped,
+		"failures": len(failures),
+	}).Info("application stopped")
+
+	if len(failures) > 0 {
+		return ExitCodeShutdownError, errors.Join(failures...)
+	}
+
+	return ExitCodeSuccess, nil
+}
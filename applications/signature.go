@@ -1,34 +1,42 @@
 package applications
 
 import (
-	"crypto/hmac"
-	"crypto/rand"
+	"crypto"
 	"crypto/rsa"
-	"crypto/sha1"
-	"crypto/sha512"
-	"crypto/x509"
-	"encoding/base64"
-	"encoding/pem"
-	"hash"
-	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/MrAndreID/goechoms/configs"
+	"github.com/MrAndreID/goechoms/signatures"
 
+	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 )
 
-// RSA Ciphers : RSA/NONE/OAEPWithSHA1AndMGF1Padding
-// For Testing : https://8gwifi.org/RSAFunctionality?keysize=512
-func (app *Application) DecryptSignature(cfg *configs.Config, encryptedData string) (*string, error) {
+type SignatureKeys struct {
+	Config            *configs.Config
+	PrivateKey        *rsa.PrivateKey
+	OAEPHash          crypto.Hash
+	Algorithms        map[string]signatures.Algorithm
+	ResponseAlgorithm signatures.Algorithm
+}
+
+// In the hmac mode the only algorithm has an empty key ID, otherwise the key ID of a public key file is its file name without the extension.
+// Responses are signed with the same HMAC in the hmac mode, otherwise with RSA-PSS (SHA-256) and the RSA_OAEP_KEY.
+func NewSignatureKeys(cfg *configs.Config) (*SignatureKeys, error) {
 	var (
-		tag    string    = "Applications.Signature.DecryptSignature."
-		hash   hash.Hash = sha1.New()
-		random io.Reader = rand.Reader
+		tag  string         = "Applications.Signature.NewSignatureKeys."
+		keys *SignatureKeys = &SignatureKeys{
+			Config:     cfg,
+			Algorithms: map[string]signatures.Algorithm{},
+		}
+		err error
 	)
 
-	privateKeyBlock, _ := pem.Decode([]byte(cfg.RSAOAEPKey))
-
-	privateKey, err := x509.ParsePKCS1PrivateKey(privateKeyBlock.Bytes)
+	keys.PrivateKey, err = signatures.ParseRSAPrivateKey([]byte(cfg.RSAOAEPKey))
 
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
@@ -39,48 +47,164 @@ func (app *Application) DecryptSignature(cfg *configs.Config, encryptedData stri
 		return nil, err
 	}
 
-	decodedData, err := base64.StdEncoding.DecodeString(encryptedData)
+	keys.OAEPHash, err = signatures.ParseHash(cfg.SignatureOAEPHash)
 
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"tag":   tag + "02",
 			"error": err.Error(),
-		}).Error("failed to decode base64 for encrypted data")
+		}).Error("failed to parse oaep hash")
+
+		return nil, err
+	}
+
+	if cfg.SignatureMode == signatures.ModeHMAC {
+		hmacHash, err := signatures.ParseHash(cfg.SignatureHMACHash)
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "03",
+				"error": err.Error(),
+			}).Error("failed to parse hmac hash")
+
+			return nil, err
+		}
+
+		keys.Algorithms[""] = &signatures.HMAC{Hash: hmacHash, SecretKey: []byte(cfg.SecretKey)}
+		keys.ResponseAlgorithm = keys.Algorithms[""]
+
+		return keys, nil
+	}
+
+	keys.ResponseAlgorithm = &signatures.RSAPSS{Hash: crypto.SHA256, PrivateKey: keys.PrivateKey, PublicKey: &keys.PrivateKey.PublicKey}
+
+	for _, path := range cfg.SignaturePublicKeyFiles {
+		content, err := os.ReadFile(path)
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "04",
+				"path":  path,
+				"error": err.Error(),
+			}).Error("failed to read signature public key file")
+
+			return nil, err
+		}
+
+		publicKey, err := signatures.ParsePublicKey(content)
+
+		if err == nil {
+			keys.Algorithms[strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))], err = signatures.NewAlgorithm(cfg.SignatureMode, crypto.SHA256, publicKey)
+		}
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "05",
+				"path":  path,
+				"error": err.Error(),
+			}).Error("failed to parse signature public key file")
+
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+// The keys are parsed once, and again only after a reload replaced the configuration.
+func (app *Application) SignatureKeys(cfg *configs.Config) (*SignatureKeys, error) {
+	if keys := app.signatureKeys.Load(); keys != nil && keys.Config == cfg {
+		return keys, nil
+	}
+
+	keys, err := NewSignatureKeys(cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	app.signatureKeys.Store(keys)
+
+	return keys, nil
+}
+
+func (app *Application) DecryptSignature(cfg *configs.Config, encryptedData string) (*string, error) {
+	keys, err := app.SignatureKeys(cfg)
 
+	if err != nil {
 		return nil, err
 	}
 
-	decryptedData, err := rsa.DecryptOAEP(hash, random, privateKey, decodedData, nil)
+	decryptedData, err := signatures.DecryptTransactionID(keys.PrivateKey, keys.OAEPHash, encryptedData)
 
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
-			"tag":   tag + "03",
+			"tag":   "Applications.Signature.DecryptSignature.01",
 			"error": err.Error(),
 		}).Error("failed to decrypt data")
 
 		return nil, err
 	}
 
-	resultData := string(decryptedData)
+	return &decryptedData, nil
+}
+
+// Without a key ID the only public key is used, so a key ID is required once several keys are active.
+func (app *Application) VerifySignature(cfg *configs.Config, keyID string, payload string, signature string) error {
+	keys, err := app.SignatureKeys(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	if cfg.SignatureMode == signatures.ModeHMAC {
+		keyID = ""
+	}
 
-	return &resultData, nil
+	algorithm, ok := keys.Algorithms[keyID]
+
+	if !ok && keyID == "" && len(keys.Algorithms) == 1 {
+		for _, only := range keys.Algorithms {
+			algorithm, ok = only, true
+		}
+	}
+
+	if !ok {
+		return signatures.ErrKeyNotFound
+	}
+
+	return algorithm.Verify(payload, signature)
 }
 
-func (app *Application) VerifySignature(cfg *configs.Config, payload string) (*string, error) {
-	mac := hmac.New(sha512.New, []byte(cfg.SecretKey))
+// Responses that are already committed (for example JSONP) can not be signed anymore and are left as they are.
+func (app *Application) SignResponse(c echo.Context, body []byte) error {
+	var cfg *configs.Config = app.ConfigHolder.Get()
+
+	if c.Response().Committed {
+		return nil
+	}
+
+	keys, err := app.SignatureKeys(cfg)
+
+	if err != nil {
+		return err
+	}
 
-	_, err := mac.Write([]byte(payload))
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	signature, err := signatures.SignResponse(keys.ResponseAlgorithm, c.Response().Status, timestamp, body)
 
 	if err != nil {
-		logrus.WithFields(logrus.Fields{
-			"tag":   "Applications.Signature.VerifySignature.01",
+		logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
+			"tag":   "Applications.Signature.SignResponse.01",
 			"error": err.Error(),
-		}).Error("failed to write from payload")
+		}).Error("failed to sign response")
 
-		return nil, err
+		return err
 	}
 
-	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	c.Response().Header().Set(cfg.ResponseSignatureName, signature)
+	c.Response().Header().Set(cfg.ResponseSignatureTimestampName, timestamp)
 
-	return &sign, nil
+	return nil
 }
@@ -0,0 +1,29 @@
+package types
+
+type Identity struct {
+	Type    string   `json:"type"`
+	Subject string   `json:"subject"`
+	Name    string   `json:"name"`
+	Scopes  []string `json:"scopes"`
+	Roles   []string `json:"roles"`
+}
+
+func (identity *Identity) HasRole(role string) bool {
+	for _, value := range identity.Roles {
+		if value == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (identity *Identity) HasScope(scope string) bool {
+	for _, value := range identity.Scopes {
+		if value == "*" || value == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -11,7 +11,13 @@ type PaginatorRequest struct {
 
 type GetUserRequest struct {
 	PaginatorRequest
-	ID string `query:"id" json:"id"`
+	ID    string `query:"id" json:"id"`
+	Email string `query:"email" json:"email"`
+}
+
+type ShowUserRequest struct {
+	ID      string `param:"id" json:"id"`
+	Include string `query:"include" json:"include"`
 }
 
 type CreateUserRequest struct {
@@ -20,7 +26,8 @@ type CreateUserRequest struct {
 }
 
 type CreateEmailRequest struct {
-	Email string `json:"email"`
+	Email   string `json:"email"`
+	Primary bool   `json:"primary"`
 }
 
 type EditUserRequest struct {
@@ -32,3 +39,35 @@ type EditUserRequest struct {
 type DeleteUserRequest struct {
 	ID string `param:"id" json:"id"`
 }
+
+type GetUserEmailRequest struct {
+	ID string `param:"id" json:"id"`
+}
+
+type ShowUserEmailRequest struct {
+	ID      string `param:"id" json:"id"`
+	EmailID string `param:"emailId" json:"emailId"`
+}
+
+type CreateUserEmailRequest struct {
+	ID      string `param:"id" json:"id"`
+	Email   string `json:"email"`
+	Primary bool   `json:"primary"`
+}
+
+type DeleteUserEmailRequest struct {
+	ID      string `param:"id" json:"id"`
+	EmailID string `param:"emailId" json:"emailId"`
+}
+
+type GetAuditLogRequest struct {
+	PaginatorRequest
+	UserID    string `query:"userId" json:"userId"`
+	Actor     string `query:"actor" json:"actor"`
+	StartDate string `query:"startDate" json:"startDate"`
+	EndDate   string `query:"endDate" json:"endDate"`
+}
+
+type GetPermissionRequest struct {
+	ServiceKeyID string `query:"serviceKeyId" json:"serviceKeyId"`
+}
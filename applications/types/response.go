@@ -18,3 +18,21 @@ type HTTPResponse struct {
 	StatusCode int
 	Error      error
 }
+
+type HealthResponse struct {
+	Status       string                 `json:"status"`
+	Dependencies map[string]HealthCheck `json:"dependencies"`
+}
+
+type HealthCheck struct {
+	Status   string `json:"status"`
+	Required bool   `json:"required"`
+	Latency  string `json:"latency"`
+	Error    string `json:"error,omitempty"`
+}
+
+type PermissionResponse struct {
+	Identity    *Identity       `json:"identity"`
+	Permissions []string        `json:"permissions"`
+	Routes      map[string]bool `json:"routes"`
+}
@@ -3,6 +3,7 @@ package types
 import (
 	"errors"
 	"regexp"
+	"strings"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -53,6 +54,50 @@ func DatetimeValidation(field string) validation.RuleFunc {
 	}
 }
 
+func IncludeValidation(field string, allowed ...string) validation.RuleFunc {
+	return func(value interface{}) error {
+		val, _ := value.(string)
+
+		if val == "" {
+			return nil
+		}
+
+		for _, item := range strings.Split(val, ",") {
+			found := false
+
+			for _, name := range allowed {
+				if strings.TrimSpace(item) == name {
+					found = true
+				}
+			}
+
+			if !found {
+				return errors.New("The " + field + " must be a list of " + strings.Join(allowed, ", "))
+			}
+		}
+
+		return nil
+	}
+}
+
+func PrimaryEmailValidation(value interface{}) error {
+	val, _ := value.([]CreateEmailRequest)
+
+	primary := 0
+
+	for _, email := range val {
+		if email.Primary {
+			primary++
+		}
+	}
+
+	if primary > 1 {
+		return errors.New("Only one of the emails can be primary")
+	}
+
+	return nil
+}
+
 func (r GetUserRequest) Validate() interface{} {
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.Page, is.Digit),
@@ -62,13 +107,21 @@ func (r GetUserRequest) Validate() interface{} {
 		validation.Field(&r.Search, validation.By(BlacklistValidation("search"))),
 		validation.Field(&r.DisableCalculateTotal, validation.In("true", "false")),
 		validation.Field(&r.ID, validation.By(BlacklistValidation("id"))),
+		validation.Field(&r.Email, is.Email, validation.By(BlacklistValidation("email"))),
+	)
+}
+
+func (r ShowUserRequest) Validate() interface{} {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ID, validation.Required, is.UUID, validation.By(BlacklistValidation("id"))),
+		validation.Field(&r.Include, validation.By(IncludeValidation("include", "auditLogs"))),
 	)
 }
 
 func (r CreateUserRequest) Validate() interface{} {
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.Name, validation.Required, validation.By(BlacklistValidation("name"))),
-		validation.Field(&r.Emails, validation.Required),
+		validation.Field(&r.Emails, validation.Required, validation.By(PrimaryEmailValidation)),
 	)
 }
 
@@ -82,7 +135,7 @@ func (r EditUserRequest) Validate() interface{} {
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.ID, validation.Required, is.UUID, validation.By(BlacklistValidation("id"))),
 		validation.Field(&r.Name, validation.By(BlacklistValidation("name"))),
-		validation.Field(&r.Emails),
+		validation.Field(&r.Emails, validation.By(PrimaryEmailValidation)),
 	)
 }
 
@@ -91,3 +144,51 @@ func (r DeleteUserRequest) Validate() interface{} {
 		validation.Field(&r.ID, validation.Required, is.UUID, validation.By(BlacklistValidation("id"))),
 	)
 }
+
+func (r GetUserEmailRequest) Validate() interface{} {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ID, validation.Required, is.UUID, validation.By(BlacklistValidation("id"))),
+	)
+}
+
+func (r ShowUserEmailRequest) Validate() interface{} {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ID, validation.Required, is.UUID, validation.By(BlacklistValidation("id"))),
+		validation.Field(&r.EmailID, validation.Required, is.UUID, validation.By(BlacklistValidation("emailId"))),
+	)
+}
+
+func (r CreateUserEmailRequest) Validate() interface{} {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ID, validation.Required, is.UUID, validation.By(BlacklistValidation("id"))),
+		validation.Field(&r.Email, validation.Required, is.Email, validation.By(BlacklistValidation("email"))),
+	)
+}
+
+func (r DeleteUserEmailRequest) Validate() interface{} {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ID, validation.Required, is.UUID, validation.By(BlacklistValidation("id"))),
+		validation.Field(&r.EmailID, validation.Required, is.UUID, validation.By(BlacklistValidation("emailId"))),
+	)
+}
+
+func (r GetAuditLogRequest) Validate() interface{} {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Page, is.Digit),
+		validation.Field(&r.Limit, is.Digit),
+		validation.Field(&r.OrderBy, validation.In("createdAt", "action", "actorName")),
+		validation.Field(&r.SortBy, validation.In("asc", "desc")),
+		validation.Field(&r.Search, validation.By(BlacklistValidation("search"))),
+		validation.Field(&r.DisableCalculateTotal, validation.In("true", "false")),
+		validation.Field(&r.UserID, validation.By(BlacklistValidation("userId"))),
+		validation.Field(&r.Actor, validation.By(BlacklistValidation("actor"))),
+		validation.Field(&r.StartDate, validation.By(DatetimeValidation("startDate"))),
+		validation.Field(&r.EndDate, validation.By(DatetimeValidation("endDate"))),
+	)
+}
+
+func (r GetPermissionRequest) Validate() interface{} {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.ServiceKeyID, is.UUID, validation.By(BlacklistValidation("serviceKeyId"))),
+	)
+}
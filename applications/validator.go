@@ -39,7 +39,7 @@ func (app *Application) NewCustomHTTPErrorHandler(err error, c echo.Context) {
 		report = echo.NewHTTPError(http.StatusInternalServerError, strings.ToUpper(strings.ReplaceAll(http.StatusText(http.StatusInternalServerError), " ", "_")))
 	}
 
-	logrus.WithFields(logrus.Fields{
+	logrus.WithContext(c.Request().Context()).WithFields(logrus.Fields{
 		"tag": "Applications.Validator.NewCustomHTTPErrorHandler.01",
 	}).Error(strings.ToLower(cast.ToString(report.Message)))
 
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/MrAndreID/goechoms/configs"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	logrus.SetOutput(os.Stderr)
+
+	cfg, err := configs.Load()
+
+	if err != nil {
+		var fieldErrors validation.Errors
+
+		fmt.Println("Invalid Configuration")
+
+		if !errors.As(err, &fieldErrors) {
+			fmt.Println("- " + err.Error())
+
+			os.Exit(1)
+		}
+
+		fields := make([]string, 0, len(fieldErrors))
+
+		for field := range fieldErrors {
+			fields = append(fields, field)
+		}
+
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			fmt.Println("- " + field + ": " + fieldErrors[field].Error())
+		}
+
+		os.Exit(1)
+	}
+
+	if cfg.ConfigFile != "" {
+		fmt.Println("Configuration File: " + cfg.ConfigFile)
+	}
+
+	fmt.Println("Valid Configuration")
+}
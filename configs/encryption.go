@@ -0,0 +1,36 @@
+package configs
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// Keys are written as "<key id>:<base64 key>", where the key is 16, 24 or 32 bytes long for AES-128, AES-192 or AES-256.
+func ParseEncryptionKeys(values []string) (map[string][]byte, error) {
+	keys := make(map[string][]byte, len(values))
+
+	for _, value := range values {
+		keyID, encoded, ok := strings.Cut(strings.TrimSpace(value), ":")
+
+		if !ok || keyID == "" {
+			return nil, errors.New("Invalid Encryption Key: " + keyID)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch len(key) {
+		case 16, 24, 32:
+		default:
+			return nil, errors.New("Invalid Encryption Key Size: " + keyID)
+		}
+
+		keys[keyID] = key
+	}
+
+	return keys, nil
+}
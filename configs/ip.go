@@ -0,0 +1,78 @@
+package configs
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+type IPRule struct {
+	RoutePrefix string
+	Network     *net.IPNet
+}
+
+// A single IP address is taken as a network of its own, e.g. "10.0.0.1" as "10.0.0.1/32".
+func ParseCIDR(value string) (*net.IPNet, error) {
+	value = strings.TrimSpace(value)
+
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+
+		if ip == nil {
+			return nil, errors.New("Invalid IP Address: " + value)
+		}
+
+		if ip.To4() != nil {
+			return &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, nil
+		}
+
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+
+	_, network, err := net.ParseCIDR(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return network, nil
+}
+
+func ParseCIDRs(values []string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+
+	for _, value := range values {
+		network, err := ParseCIDR(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// Route rules are written as "<route name prefix>=<cidr>", e.g. "user.=10.0.0.0/8".
+func ParseIPRules(values []string) ([]IPRule, error) {
+	var rules []IPRule
+
+	for _, value := range values {
+		routePrefix, cidr, ok := strings.Cut(value, "=")
+
+		if !ok || strings.TrimSpace(routePrefix) == "" {
+			return nil, errors.New("Invalid IP Rule: " + value)
+		}
+
+		network, err := ParseCIDR(cidr)
+
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, IPRule{RoutePrefix: strings.TrimSpace(routePrefix), Network: network})
+	}
+
+	return rules, nil
+}
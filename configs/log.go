@@ -1,49 +1,372 @@
 package configs
 
 import (
+	"compress/gzip"
+	"errors"
 	"io"
+	"math"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	rotatelogs "github.com/lestrrat-go/file-rotatelogs"
 	"github.com/sirupsen/logrus"
 )
 
-func NewBodyDumpLog() error {
-	var tag string = "Configs.Log.NewBodyDumpLog."
+type contextKey string
 
-	dir, err := os.Getwd()
+const (
+	RequestIDKey contextKey = "RequestID"
+	ClientKey    contextKey = "Client"
+)
+
+type ContextHook struct{}
+
+func (hook *ContextHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (hook *ContextHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+
+	if requestID, ok := entry.Context.Value(RequestIDKey).(string); ok && requestID != "" {
+		entry.Data["requestId"] = requestID
+	}
+
+	if client, ok := entry.Context.Value(ClientKey).(string); ok && client != "" {
+		entry.Data["client"] = client
+	}
+
+	return nil
+}
+
+type LevelFormatter struct {
+	Formatter logrus.Formatter
+	Level     logrus.Level
+	Overrides map[string]logrus.Level
+}
+
+// The tag field is matched case-insensitively against the override prefixes, the longest prefix wins.
+func (formatter *LevelFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	var (
+		level  logrus.Level = formatter.Level
+		length int
+	)
+
+	if tag, ok := entry.Data["tag"].(string); ok {
+		for prefix, overrideLevel := range formatter.Overrides {
+			if len(prefix) > length && strings.HasPrefix(strings.ToLower(tag), prefix) {
+				level = overrideLevel
+				length = len(prefix)
+			}
+		}
+	}
+
+	if entry.Level > level {
+		return nil, nil
+	}
+
+	return formatter.Formatter.Format(entry)
+}
+
+type rotatedLogHandler struct {
+	Directory     string
+	LinkName      string
+	Compress      bool
+	MaxAge        time.Duration
+	RotationCount int
+}
+
+func (handler *rotatedLogHandler) Handle(event rotatelogs.Event) {
+	var tag string = "Configs.Log.Handle."
+
+	rotatedEvent, ok := event.(*rotatelogs.FileRotatedEvent)
+
+	if !ok || rotatedEvent.PreviousFile() == "" {
+		return
+	}
+
+	if handler.Compress {
+		if err := compressLogFile(rotatedEvent.PreviousFile()); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "01",
+				"path":  rotatedEvent.PreviousFile(),
+				"error": err.Error(),
+			}).Error("failed to compress rotated log file")
+		}
+	}
+
+	if err := handler.purge(rotatedEvent.CurrentFile()); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "02",
+			"error": err.Error(),
+		}).Error("failed to purge old log files")
+	}
+}
+
+func (handler *rotatedLogHandler) purge(currentFile string) error {
+	matches, err := filepath.Glob(filepath.Join(handler.Directory, "*.log*"))
+
+	if err != nil {
+		return err
+	}
+
+	var files []os.FileInfo
+
+	paths := map[string]string{}
+
+	for _, path := range matches {
+		if path == currentFile || path == handler.LinkName {
+			continue
+		}
+
+		fileInfo, err := os.Lstat(path)
+
+		if err != nil || !fileInfo.Mode().IsRegular() {
+			continue
+		}
+
+		files = append(files, fileInfo)
+		paths[fileInfo.Name()] = path
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().After(files[j].ModTime())
+	})
+
+	for i, fileInfo := range files {
+		expired := handler.MaxAge > 0 && time.Since(fileInfo.ModTime()) > handler.MaxAge
+
+		if handler.RotationCount > 0 && i >= handler.RotationCount {
+			expired = true
+		}
+
+		if expired {
+			if err := os.Remove(paths[fileInfo.Name()]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func compressLogFile(path string) error {
+	source, err := os.Open(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer source.Close()
+
+	target, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+
+	if err != nil {
+		return err
+	}
+
+	writer := gzip.NewWriter(target)
+
+	if _, err := io.Copy(writer, source); err != nil {
+		target.Close()
+
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		target.Close()
+
+		return err
+	}
+
+	if err := target.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+func ParseLogLevels(cfg *Config) (logrus.Level, map[string]logrus.Level, error) {
+	level, err := logrus.ParseLevel(cfg.LogLevel)
+
+	if err != nil {
+		return level, nil, err
+	}
+
+	overrides := map[string]logrus.Level{}
+
+	for _, value := range cfg.LogLevelOverrides {
+		index := strings.LastIndex(value, ":")
+
+		if index <= 0 {
+			return level, nil, errors.New("Invalid Log Level Override: " + value)
+		}
+
+		overrideLevel, err := logrus.ParseLevel(value[index+1:])
+
+		if err != nil {
+			return level, nil, err
+		}
+
+		overrides[strings.ToLower(strings.TrimSpace(value[:index]))] = overrideLevel
+	}
+
+	return level, overrides, nil
+}
+
+func minimumLogLevel(level logrus.Level, overrides map[string]logrus.Level) logrus.Level {
+	for _, overrideLevel := range overrides {
+		if overrideLevel > level {
+			level = overrideLevel
+		}
+	}
+
+	return level
+}
+
+func SetLogLevel(cfg *Config) error {
+	level, overrides, err := ParseLogLevels(cfg)
 
 	if err != nil {
 		logrus.WithFields(logrus.Fields{
-			"tag":   tag + "01",
+			"tag":   "Configs.Log.SetLogLevel.01",
 			"error": err.Error(),
-		}).Error("failed to get root path")
+		}).Error("failed to parse log level")
 
 		return err
 	}
 
-	logfile, err := rotatelogs.New(
-		dir+"/storages/logs/%Y%m%d.log",
-		rotatelogs.WithLinkName(dir+"/storages/logs/master.log"),
-		rotatelogs.WithRotationTime(24*time.Hour),
-		rotatelogs.WithMaxAge(-1),
-		rotatelogs.WithRotationCount(365),
+	formatter, ok := logrus.StandardLogger().Formatter.(*LevelFormatter)
+
+	if !ok {
+		return errors.New("Log Is Not Initiated")
+	}
+
+	logrus.SetFormatter(&LevelFormatter{
+		Formatter: formatter.Formatter,
+		Level:     level,
+		Overrides: overrides,
+	})
+	logrus.SetLevel(minimumLogLevel(level, overrides))
+
+	return nil
+}
+
+func NewLog(cfg *Config) error {
+	var (
+		tag       string = "Configs.Log.NewLog."
+		formatter logrus.Formatter
+		output    io.Writer
 	)
 
+	level, overrides, err := ParseLogLevels(cfg)
+
 	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.Error(),
+		}).Error("failed to parse log level")
+
+		return err
+	}
+
+	switch cfg.LogFormat {
+	case "json":
+		formatter = &logrus.JSONFormatter{DisableHTMLEscape: true}
+	case "text":
+		formatter = &logrus.TextFormatter{FullTimestamp: true}
+	default:
+		err = errors.New("Log Format Not Found")
+
 		logrus.WithFields(logrus.Fields{
 			"tag":   tag + "02",
 			"error": err.Error(),
-		}).Error("failed to create a new rotate log")
+		}).Error("failed to set log format")
+
+		return err
+	}
+
+	if cfg.LogOutput == "file" || cfg.LogOutput == "both" {
+		directory := cfg.LogDirectory
+
+		if !filepath.IsAbs(directory) {
+			dir, err := os.Getwd()
+
+			if err != nil {
+				logrus.WithFields(logrus.Fields{
+					"tag":   tag + "03",
+					"error": err.Error(),
+				}).Error("failed to get root path")
+
+				return err
+			}
+
+			directory = filepath.Join(dir, directory)
+		}
+
+		handler := &rotatedLogHandler{
+			Directory:     directory,
+			LinkName:      filepath.Join(directory, "master.log"),
+			Compress:      cfg.LogCompress,
+			MaxAge:        time.Duration(cfg.LogMaxAge) * 24 * time.Hour,
+			RotationCount: cfg.LogRotationCount,
+		}
+
+		// Retention is left to the handler, which also sees the compressed files, so rotatelogs is set to never delete a file.
+		logfile, err := rotatelogs.New(
+			filepath.Join(directory, "%Y%m%d.log"),
+			rotatelogs.WithLinkName(handler.LinkName),
+			rotatelogs.WithRotationTime(time.Duration(cfg.LogRotationTime)*time.Hour),
+			rotatelogs.WithRotationSize(int64(cfg.LogRotationSize)*1024*1024),
+			rotatelogs.WithHandler(handler),
+			rotatelogs.WithMaxAge(-1),
+			rotatelogs.WithRotationCount(math.MaxUint32),
+		)
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "04",
+				"error": err.Error(),
+			}).Error("failed to create a new rotate log")
+
+			return err
+		}
+
+		output = logfile
+	}
+
+	switch cfg.LogOutput {
+	case "stdout":
+		output = os.Stdout
+	case "file":
+	case "both":
+		output = io.MultiWriter(os.Stdout, output)
+	default:
+		err = errors.New("Log Output Not Found")
+
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "05",
+			"error": err.Error(),
+		}).Error("failed to set log output")
 
 		return err
 	}
 
-	logrus.SetFormatter(&logrus.JSONFormatter{DisableHTMLEscape: true})
-	logrus.SetOutput(io.MultiWriter(os.Stdout, logfile))
-	logrus.SetLevel(logrus.InfoLevel)
-	logrus.SetReportCaller(true)
+	logrus.SetFormatter(&LevelFormatter{
+		Formatter: formatter,
+		Level:     level,
+		Overrides: overrides,
+	})
+	logrus.SetOutput(output)
+	logrus.SetLevel(minimumLogLevel(level, overrides))
+	logrus.SetReportCaller(cfg.LogReportCaller)
+	logrus.StandardLogger().ReplaceHooks(logrus.LevelHooks{})
+	logrus.AddHook(&ContextHook{})
 
 	return nil
 }
@@ -1,18 +1,63 @@
 package configs
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/caarlos0/env/v6"
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
+	ConfigFile string `env:"CONFIG_FILE"`
+
 	AppName string `env:"APP_NAME" envDefault:"Go Echo MicroService"`
 
-	Port string `env:"PORT,notEmpty"`
+	AppEnv   string `env:"APP_ENV" envDefault:"production"`
+	AppDebug bool   `env:"APP_DEBUG"`
+
+	Port string `env:"PORT"`
+
+	TrustRequestID bool `env:"TRUST_REQUEST_ID" envDefault:"true"`
 
 	TimeZone string `env:"TIME_ZONE" envDefault:"Asia/Jakarta"`
 
+	LogLevel          string   `env:"LOG_LEVEL" reload:"true"`
+	LogLevelOverrides []string `env:"LOG_LEVEL_OVERRIDES" envSeparator:"," reload:"true"`
+	LogFormat         string   `env:"LOG_FORMAT" envDefault:"json"`
+	LogOutput         string   `env:"LOG_OUTPUT" envDefault:"both"`
+	LogDirectory      string   `env:"LOG_DIRECTORY" envDefault:"storages/logs"`
+	LogRotationTime   int      `env:"LOG_ROTATION_TIME" envDefault:"24"`
+	LogRotationSize   int      `env:"LOG_ROTATION_SIZE" envDefault:"0"`
+	LogRotationCount  int      `env:"LOG_ROTATION_COUNT" envDefault:"365"`
+	LogMaxAge         int      `env:"LOG_MAX_AGE" envDefault:"0"`
+	LogCompress       bool     `env:"LOG_COMPRESS" envDefault:"false"`
+	LogReportCaller   bool     `env:"LOG_REPORT_CALLER" envDefault:"true"`
+
+	LogRedactHeaders      []string `env:"LOG_REDACT_HEADERS" envSeparator:"," envDefault:"Service-Key,Authorization,Cookie,Set-Cookie"`
+	LogRedactFields       []string `env:"LOG_REDACT_FIELDS" envSeparator:"," envDefault:"email,password"`
+	LogRedactStyle        string   `env:"LOG_REDACT_STYLE" envDefault:"full"`
+	LogBodyDump           bool     `env:"LOG_BODY_DUMP"`
+	LogBodyMaxSize        int      `env:"LOG_BODY_MAX_SIZE" envDefault:"4096"`
+	LogBodyDumpSkipRoutes []string `env:"LOG_BODY_DUMP_SKIP_ROUTES" envSeparator:"," envDefault:"metrics"`
+
+	EchoLogLevel string `env:"ECHO_LOG_LEVEL"`
+
+	SecureDevelopment     bool   `env:"SECURE_DEVELOPMENT"`
+	ContentSecurityPolicy string `env:"CONTENT_SECURITY_POLICY"`
+	HSTSMaxAge            int    `env:"HSTS_MAX_AGE"`
+	HSTSIncludeSubdomains bool   `env:"HSTS_INCLUDE_SUBDOMAINS"`
+	HSTSPreload           bool   `env:"HSTS_PRELOAD"`
+
 	UseDatabase        bool   `env:"USE_DATABASE" envDefault:"false"`
 	DatabaseConnection string `env:"DATABASE_CONNECTION"`
 	DatabaseHost       string `env:"DATABASE_HOST"`
@@ -31,60 +76,283 @@ type Config struct {
 	RedisUsername string `env:"REDIS_USERNAME"`
 	RedisPassword string `env:"REDIS_PASSWORD"`
 
-	AllowedOrigins []string `env:"ALLOWED_ORIGINS" envSeparator:","`
+	UseMetrics       bool   `env:"USE_METRICS" envDefault:"false"`
+	MetricsNamespace string `env:"METRICS_NAMESPACE" envDefault:"goechoms"`
+
+	AllowedOrigins []string `env:"ALLOWED_ORIGINS" envSeparator:"," reload:"true"`
+
+	TrustedProxies   []string `env:"TRUSTED_PROXIES" envSeparator:","`
+	ClientIPHeader   string   `env:"CLIENT_IP_HEADER" envDefault:"X-Forwarded-For"`
+	IPAllowList      []string `env:"IP_ALLOW_LIST" envSeparator:"," reload:"true"`
+	IPDenyList       []string `env:"IP_DENY_LIST" envSeparator:"," reload:"true"`
+	IPRouteAllowList []string `env:"IP_ROUTE_ALLOW_LIST" envSeparator:"," reload:"true"`
+	IPRouteDenyList  []string `env:"IP_ROUTE_DENY_LIST" envSeparator:"," reload:"true"`
+
+	UseSignature               bool     `env:"USE_SIGNATURE" envDefault:"false"`
+	SignatureName              string   `env:"SIGNATURE_NAME"`
+	SignatureValidationName    string   `env:"SIGNATURE_VALIDATION_NAME"`
+	SignatureTransactionIDName string   `env:"SIGNATURE_TRANSACTION_ID_NAME"`
+	SignatureReplayTTL         int      `env:"SIGNATURE_REPLAY_TTL" envDefault:"86400"`
+	SignatureReplayCacheSize   int      `env:"SIGNATURE_REPLAY_CACHE_SIZE" envDefault:"100000"`
+	SignatureTimestamp         string   `env:"SIGNATURE_TIMESTAMP" envDefault:"disabled"`
+	SignatureClockSkew         int      `env:"SIGNATURE_CLOCK_SKEW" envDefault:"300" reload:"true"`
+	SignatureMode              string   `env:"SIGNATURE_MODE" envDefault:"hmac"`
+	SignatureOAEPHash          string   `env:"SIGNATURE_OAEP_HASH" envDefault:"sha1"`
+	SignatureHMACHash          string   `env:"SIGNATURE_HMAC_HASH" envDefault:"sha512"`
+	SignaturePublicKeyFiles    []string `env:"SIGNATURE_PUBLIC_KEY_FILES" envSeparator:","`
+	SignatureKeyIDName         string   `env:"SIGNATURE_KEY_ID_NAME" envDefault:"X-Signature-Key-ID"`
+	SignatureVersionName       string   `env:"SIGNATURE_VERSION_NAME" envDefault:"X-Signature-Version"`
+	SignatureVersions          []string `env:"SIGNATURE_VERSIONS" envSeparator:"," envDefault:"1,2" reload:"true"`
+
+	UseResponseSignature           bool   `env:"USE_RESPONSE_SIGNATURE" envDefault:"false"`
+	ResponseSignatureName          string `env:"RESPONSE_SIGNATURE_NAME" envDefault:"X-Response-Signature"`
+	ResponseSignatureTimestampName string `env:"RESPONSE_SIGNATURE_TIMESTAMP_NAME" envDefault:"X-Response-Timestamp"`
 
-	UseSignature               bool   `env:"USE_SIGNATURE" envDefault:"false"`
-	SignatureName              string `env:"SIGNATURE_NAME"`
-	SignatureValidationName    string `env:"SIGNATURE_VALIDATION_NAME"`
-	SignatureTransactionIDName string `env:"SIGNATURE_TRANSACTION_ID_NAME"`
+	RSAOAEPKey string `env:"RSA_OAEP_KEY" reload:"true"`
 
-	RSAOAEPKey string `env:"RSA_OAEP_KEY"`
+	SecretKey string `env:"SECRET_KEY" reload:"true"`
 
-	SecretKey string `env:"SECRET_KEY"`
+	ServiceKeyStore             string   `env:"SERVICE_KEY_STORE" envDefault:"config"`
+	ServiceKey                  string   `env:"SERVICE_KEY" reload:"true"`
+	ServiceKeyPrevious          string   `env:"SERVICE_KEY_PREVIOUS" reload:"true"`
+	ServiceKeyPreviousExpiresAt string   `env:"SERVICE_KEY_PREVIOUS_EXPIRES_AT" reload:"true"`
+	ServiceKeyCacheTTL          int      `env:"SERVICE_KEY_CACHE_TTL" envDefault:"60" reload:"true"`
+	ServiceKeyRoles             []string `env:"SERVICE_KEY_ROLES" envSeparator:"," reload:"true"`
 
-	ServiceKey string `env:"SERVICE_KEY"`
+	UseJWT            bool     `env:"USE_JWT" envDefault:"false"`
+	JWTAlgorithms     []string `env:"JWT_ALGORITHMS" envSeparator:"," envDefault:"HS256,RS256,ES256"`
+	JWTSecret         string   `env:"JWT_SECRET"`
+	JWTPublicKeyFiles []string `env:"JWT_PUBLIC_KEY_FILES" envSeparator:","`
+	JWTJWKSFile       string   `env:"JWT_JWKS_FILE"`
+	JWTIssuer         string   `env:"JWT_ISSUER"`
+	JWTAudience       string   `env:"JWT_AUDIENCE"`
+	JWTLeeway         int      `env:"JWT_LEEWAY" envDefault:"0"`
+	JWTScopeClaim     string   `env:"JWT_SCOPE_CLAIM" envDefault:"scope"`
+	JWTRoleClaim      string   `env:"JWT_ROLE_CLAIM" envDefault:"roles"`
 
-	DefaultTimeout int `env:"DEFAULT_TIMEOUT" envDefault:"1"`
+	UsePermission      bool     `env:"USE_PERMISSION" envDefault:"false"`
+	PermissionStore    string   `env:"PERMISSION_STORE" envDefault:"config"`
+	PermissionRoles    []string `env:"PERMISSION_ROLES" envSeparator:"," envDefault:"admin=*" reload:"true"`
+	PermissionRoutes   []string `env:"PERMISSION_ROUTES" envSeparator:"," envDefault:"user.index=user:read,user.show=user:read,user.create=user:write,user.edit=user:write,user.delete=user:delete,user.email.index=user:read,user.email.show=user:read,user.email.create=user:write,user.email.delete=user:write,audit-log.index=audit:read,admin.permission.show=permission:read" reload:"true"`
+	PermissionCacheTTL int      `env:"PERMISSION_CACHE_TTL" envDefault:"60" reload:"true"`
+
+	UseRateLimit     bool     `env:"USE_RATE_LIMIT" envDefault:"false"`
+	RateLimitKey     string   `env:"RATE_LIMIT_KEY" envDefault:"identity"`
+	RateLimitHeader  string   `env:"RATE_LIMIT_HEADER"`
+	RateLimitDefault string   `env:"RATE_LIMIT_DEFAULT" reload:"true"`
+	RateLimitIP      string   `env:"RATE_LIMIT_IP" envDefault:"300/60" reload:"true"`
+	RateLimitRoutes  []string `env:"RATE_LIMIT_ROUTES" envSeparator:"," reload:"true"`
+
+	UseIdempotency         bool   `env:"USE_IDEMPOTENCY" envDefault:"false"`
+	IdempotencyStore       string `env:"IDEMPOTENCY_STORE" envDefault:"memory"`
+	IdempotencyTTL         int    `env:"IDEMPOTENCY_TTL" envDefault:"86400" reload:"true"`
+	IdempotencyLockTimeout int    `env:"IDEMPOTENCY_LOCK_TIMEOUT" envDefault:"10" reload:"true"`
+
+	UseFieldEncryption   bool     `env:"USE_FIELD_ENCRYPTION" envDefault:"false"`
+	FieldEncryptionKeys  []string `env:"FIELD_ENCRYPTION_KEYS" envSeparator:","`
+	FieldEncryptionKeyID string   `env:"FIELD_ENCRYPTION_KEY_ID"`
+	FieldBlindIndexKey   string   `env:"FIELD_BLIND_INDEX_KEY"`
+
+	DefaultTimeout int `env:"DEFAULT_TIMEOUT" envDefault:"1" reload:"true"`
+
+	ShutdownTimeout int `env:"SHUTDOWN_TIMEOUT" envDefault:"10"`
+
+	ConfigWatchInterval int `env:"CONFIG_WATCH_INTERVAL" envDefault:"0"`
 
 	CurrencyURL string `env:"CURRENCY_URL"`
+
+	HealthCurrencyCheck string `env:"HEALTH_CURRENCY_CHECK" envDefault:"disabled"`
 }
 
+var configFileFlag *string = flag.String("config", "", "Path to a YAML or JSON configuration file")
+
 func New() (*Config, error) {
-	var (
-		tag string = "Configs.Main.New."
-		cfg Config
-	)
+	var tag string = "Configs.Main.New."
 
 	LoadVersion()
 
 	logrus.SetFormatter(&logrus.JSONFormatter{})
 
-	if err := godotenv.Load(); err != nil {
+	cfg, err := Load()
+
+	if err != nil {
 		logrus.WithFields(logrus.Fields{
 			"tag":   tag + "01",
 			"error": err.Error(),
-		}).Error("failed to load environment file")
+		}).Error("failed to load configuration")
 
 		return nil, err
 	}
 
-	if err := env.Parse(&cfg); err != nil {
+	LoadProfile(cfg)
+
+	if err := NewLog(cfg); err != nil {
 		logrus.WithFields(logrus.Fields{
 			"tag":   tag + "02",
 			"error": err.Error(),
-		}).Error("failed to parse environment")
+		}).Error("failed to initiate log")
+
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func Load() (*Config, error) {
+	cfg, err := Parse()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   "Configs.Main.Load.01",
+			"error": err.Error(),
+		}).Error("invalid configuration")
+
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Sources are layered from lowest to highest precedence: defaults, APP_ENV profile defaults, configuration file, .env file and environment variables.
+// The configuration is not validated, so tools that only need a few fields can use it without a valid server configuration.
+func Parse() (*Config, error) {
+	var (
+		tag         string            = "Configs.Main.Parse."
+		environment map[string]string = map[string]string{}
+		cfg         Config
+	)
+
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+
+	dotEnv, err := godotenv.Read()
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.Error(),
+		}).Error("failed to load environment file")
 
 		return nil, err
 	}
 
-	if err := NewBodyDumpLog(); err != nil {
+	configFile := *configFileFlag
+
+	if configFile == "" {
+		configFile = os.Getenv("CONFIG_FILE")
+	}
+
+	if configFile == "" {
+		configFile = dotEnv["CONFIG_FILE"]
+	}
+
+	if configFile != "" {
+		fileEnvironment, err := ReadConfigFile(configFile)
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "02",
+				"path":  configFile,
+				"error": err.Error(),
+			}).Error("failed to read configuration file")
+
+			return nil, err
+		}
+
+		for key, value := range fileEnvironment {
+			environment[key] = value
+		}
+	}
+
+	for key, value := range dotEnv {
+		environment[key] = value
+	}
+
+	for _, variable := range os.Environ() {
+		if key, value, ok := strings.Cut(variable, "="); ok {
+			environment[key] = value
+		}
+	}
+
+	profile, ok := environment["APP_ENV"]
+
+	if !ok {
+		profile = "production"
+	}
+
+	for key, value := range ProfileDefaults(profile) {
+		if _, ok := environment[key]; !ok {
+			environment[key] = value
+		}
+	}
+
+	if err := env.Parse(&cfg, env.Options{Environment: environment}); err != nil {
 		logrus.WithFields(logrus.Fields{
 			"tag":   tag + "03",
 			"error": err.Error(),
-		}).Error("failed to initiate a body dump for log")
+		}).Error("failed to parse environment")
 
 		return nil, err
 	}
 
+	cfg.ConfigFile = configFile
+
 	return &cfg, nil
 }
+
+// Keys in the configuration file are the environment variable names, lists are joined with a comma.
+func ReadConfigFile(path string) (map[string]string, error) {
+	var values map[string]interface{}
+
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &values)
+	case ".json":
+		// Numbers are kept as written, since float64 would print 1000000 as 1e+06.
+		decoder := json.NewDecoder(bytes.NewReader(content))
+
+		decoder.UseNumber()
+
+		err = decoder.Decode(&values)
+	default:
+		err = errors.New("Configuration File Format Not Found")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	environment := make(map[string]string, len(values))
+
+	for key, value := range values {
+		switch typed := value.(type) {
+		case nil:
+			environment[key] = ""
+		case []interface{}:
+			items := make([]string, len(typed))
+
+			for i, item := range typed {
+				items[i] = fmt.Sprint(item)
+			}
+
+			environment[key] = strings.Join(items, ",")
+		case map[string]interface{}:
+			return nil, errors.New("Nested Configuration Is Not Supported: " + key)
+		default:
+			environment[key] = fmt.Sprint(typed)
+		}
+	}
+
+	return environment, nil
+}
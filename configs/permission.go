@@ -0,0 +1,26 @@
+package configs
+
+import (
+	"errors"
+	"strings"
+)
+
+// Rules are written as "<name>=<permission>" and repeated for each permission, e.g. "editor=user:read,editor=user:write".
+func ParsePermissionRules(values []string) (map[string][]string, error) {
+	rules := map[string][]string{}
+
+	for _, value := range values {
+		name, permission, ok := strings.Cut(value, "=")
+
+		name = strings.TrimSpace(name)
+		permission = strings.TrimSpace(permission)
+
+		if !ok || name == "" || permission == "" {
+			return nil, errors.New("Invalid Permission Rule: " + value)
+		}
+
+		rules[name] = append(rules[name], permission)
+	}
+
+	return rules, nil
+}
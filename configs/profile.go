@@ -0,0 +1,42 @@
+package configs
+
+// Profile defaults sit between the field defaults and the configuration file, so every setting can still be overridden.
+var profileDefaults map[string]map[string]string = map[string]map[string]string{
+	"development": {
+		"APP_DEBUG":               "true",
+		"ECHO_LOG_LEVEL":          "debug",
+		"LOG_LEVEL":               "debug",
+		"LOG_BODY_DUMP":           "true",
+		"SECURE_DEVELOPMENT":      "true",
+		"CONTENT_SECURITY_POLICY": "default-src 'self'",
+		"HSTS_MAX_AGE":            "0",
+		"HSTS_INCLUDE_SUBDOMAINS": "false",
+		"HSTS_PRELOAD":            "false",
+	},
+	"staging": {
+		"APP_DEBUG":               "false",
+		"ECHO_LOG_LEVEL":          "info",
+		"LOG_LEVEL":               "info",
+		"LOG_BODY_DUMP":           "true",
+		"SECURE_DEVELOPMENT":      "false",
+		"CONTENT_SECURITY_POLICY": "default-src 'self'",
+		"HSTS_MAX_AGE":            "3600",
+		"HSTS_INCLUDE_SUBDOMAINS": "false",
+		"HSTS_PRELOAD":            "false",
+	},
+	"production": {
+		"APP_DEBUG":               "false",
+		"ECHO_LOG_LEVEL":          "warn",
+		"LOG_LEVEL":               "info",
+		"LOG_BODY_DUMP":           "false",
+		"SECURE_DEVELOPMENT":      "false",
+		"CONTENT_SECURITY_POLICY": "default-src 'none'; frame-ancestors 'none'",
+		"HSTS_MAX_AGE":            "63072000",
+		"HSTS_INCLUDE_SUBDOMAINS": "true",
+		"HSTS_PRELOAD":            "true",
+	},
+}
+
+func ProfileDefaults(profile string) map[string]string {
+	return profileDefaults[profile]
+}
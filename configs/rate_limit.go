@@ -0,0 +1,72 @@
+package configs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RateLimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+// A rate limit is written as "<requests>/<seconds>", e.g. "100/60".
+func ParseRateLimit(value string) (RateLimit, error) {
+	limit, window, ok := strings.Cut(strings.TrimSpace(value), "/")
+
+	if !ok {
+		return RateLimit{}, errors.New("Invalid Rate Limit: " + value)
+	}
+
+	requests, err := strconv.Atoi(limit)
+
+	if err != nil || requests < 1 {
+		return RateLimit{}, errors.New("Invalid Rate Limit: " + value)
+	}
+
+	seconds, err := strconv.Atoi(window)
+
+	if err != nil || seconds < 1 {
+		return RateLimit{}, errors.New("Invalid Rate Limit: " + value)
+	}
+
+	return RateLimit{Limit: requests, Window: time.Second * time.Duration(seconds)}, nil
+}
+
+// Routes listed in RATE_LIMIT_ROUTES as "<route name>:<requests>/<seconds>" override RATE_LIMIT_DEFAULT.
+func ParseRateLimits(cfg *Config) (*RateLimit, map[string]RateLimit, error) {
+	var (
+		defaultRateLimit *RateLimit
+		routes           map[string]RateLimit = map[string]RateLimit{}
+	)
+
+	if cfg.RateLimitDefault != "" {
+		rateLimit, err := ParseRateLimit(cfg.RateLimitDefault)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		defaultRateLimit = &rateLimit
+	}
+
+	for _, value := range cfg.RateLimitRoutes {
+		index := strings.LastIndex(value, ":")
+
+		if index <= 0 {
+			return nil, nil, errors.New("Invalid Route Rate Limit: " + value)
+		}
+
+		rateLimit, err := ParseRateLimit(value[index+1:])
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		routes[strings.TrimSpace(value[:index])] = rateLimit
+	}
+
+	return defaultRateLimit, routes, nil
+}
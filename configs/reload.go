@@ -0,0 +1,108 @@
+package configs
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Holder struct {
+	current atomic.Pointer[Config]
+	mutex   sync.Mutex
+}
+
+func NewHolder(cfg *Config) *Holder {
+	holder := &Holder{}
+
+	holder.current.Store(cfg)
+
+	return holder
+}
+
+func (holder *Holder) Get() *Config {
+	return holder.current.Load()
+}
+
+// Only fields tagged with reload:"true" are swapped, changes to any other field are logged and ignored until a restart.
+func (holder *Holder) Reload() error {
+	var (
+		tag     string = "Configs.Reload.Reload."
+		changed []string
+		ignored []string
+	)
+
+	holder.mutex.Lock()
+
+	defer holder.mutex.Unlock()
+
+	loaded, err := Load()
+
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "01",
+			"error": err.Error(),
+		}).Error("failed to reload configuration")
+
+		return err
+	}
+
+	current := holder.Get()
+	next := *current
+
+	currentValue := reflect.ValueOf(current).Elem()
+	loadedValue := reflect.ValueOf(loaded).Elem()
+	nextValue := reflect.ValueOf(&next).Elem()
+
+	for i := 0; i < currentValue.NumField(); i++ {
+		field := currentValue.Type().Field(i)
+
+		if reflect.DeepEqual(currentValue.Field(i).Interface(), loadedValue.Field(i).Interface()) {
+			continue
+		}
+
+		if field.Tag.Get("reload") != "true" {
+			ignored = append(ignored, environmentName(field.Name))
+
+			continue
+		}
+
+		nextValue.Field(i).Set(loadedValue.Field(i))
+
+		changed = append(changed, environmentName(field.Name))
+	}
+
+	if len(ignored) > 0 {
+		logrus.WithFields(logrus.Fields{
+			"tag":    tag + "02",
+			"fields": ignored,
+		}).Warn("ignoring configuration changes that require a restart")
+	}
+
+	if len(changed) == 0 {
+		logrus.WithFields(logrus.Fields{
+			"tag": tag + "03",
+		}).Info("configuration is unchanged")
+
+		return nil
+	}
+
+	if err := SetLogLevel(&next); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "04",
+			"error": err.Error(),
+		}).Error("failed to set log level")
+
+		return err
+	}
+
+	holder.current.Store(&next)
+
+	logrus.WithFields(logrus.Fields{
+		"tag":    tag + "05",
+		"fields": changed,
+	}).Info("configuration reloaded")
+
+	return nil
+}
@@ -13,3 +13,9 @@ func LoadVersion() {
 
 	fmt.Println()
 }
+
+func LoadProfile(cfg *Config) {
+	fmt.Println("Profile: " + cfg.AppEnv)
+
+	fmt.Println()
+}
@@ -0,0 +1,273 @@
+package configs
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/sirupsen/logrus"
+)
+
+func LogLevelValidation(value interface{}) error {
+	val, _ := value.(string)
+
+	if _, err := logrus.ParseLevel(val); err != nil {
+		return errors.New("must be a valid log level")
+	}
+
+	return nil
+}
+
+func CIDRsValidation(value interface{}) error {
+	val, _ := value.([]string)
+
+	if _, err := ParseCIDRs(val); err != nil {
+		return errors.New("must be a list of ip addresses or cidr ranges")
+	}
+
+	return nil
+}
+
+func IPRulesValidation(value interface{}) error {
+	val, _ := value.([]string)
+
+	if _, err := ParseIPRules(val); err != nil {
+		return errors.New("must be a list of <route name prefix>=<cidr>")
+	}
+
+	return nil
+}
+
+func RateLimitValidation(value interface{}) error {
+	val, _ := value.(string)
+
+	if val == "" {
+		return nil
+	}
+
+	if _, err := ParseRateLimit(val); err != nil {
+		return errors.New("must be in the format <requests>/<seconds>")
+	}
+
+	return nil
+}
+
+func RateLimitRoutesValidation(value interface{}) error {
+	val, _ := value.([]string)
+
+	if _, _, err := ParseRateLimits(&Config{RateLimitRoutes: val}); err != nil {
+		return errors.New("must be a list of <route name>:<requests>/<seconds>")
+	}
+
+	return nil
+}
+
+func EncryptionKeysValidation(value interface{}) error {
+	val, _ := value.([]string)
+
+	if _, err := ParseEncryptionKeys(val); err != nil {
+		return errors.New("must be a list of <key id>:<base64 key> with 16, 24 or 32 byte keys")
+	}
+
+	return nil
+}
+
+func EncryptionKeyIDValidation(keys []string) validation.RuleFunc {
+	return func(value interface{}) error {
+		val, _ := value.(string)
+
+		parsedKeys, _ := ParseEncryptionKeys(keys)
+
+		if _, ok := parsedKeys[val]; !ok {
+			return errors.New("must be one of the key ids in FIELD_ENCRYPTION_KEYS")
+		}
+
+		return nil
+	}
+}
+
+func BlindIndexKeyValidation(value interface{}) error {
+	val, _ := value.(string)
+
+	if val == "" {
+		return nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(val)
+
+	if err != nil || len(key) < 16 {
+		return errors.New("must be a base64 key of at least 16 bytes")
+	}
+
+	return nil
+}
+
+func PermissionRulesValidation(value interface{}) error {
+	val, _ := value.([]string)
+
+	if _, err := ParsePermissionRules(val); err != nil {
+		return errors.New("must be a list of <name>=<permission>")
+	}
+
+	return nil
+}
+
+func RequiresValidation(field string) validation.RuleFunc {
+	return func(value interface{}) error {
+		return errors.New("requires " + field + " to be enabled")
+	}
+}
+
+func TimeZoneValidation(value interface{}) error {
+	val, _ := value.(string)
+
+	if _, err := time.LoadLocation(val); err != nil {
+		return errors.New("must be a valid time zone")
+	}
+
+	return nil
+}
+
+func RSAPrivateKeyValidation(value interface{}) error {
+	val, _ := value.(string)
+
+	if val == "" {
+		return nil
+	}
+
+	privateKeyBlock, _ := pem.Decode([]byte(val))
+
+	if privateKeyBlock == nil {
+		return errors.New("must be a pem encoded private key")
+	}
+
+	if _, err := x509.ParsePKCS1PrivateKey(privateKeyBlock.Bytes); err == nil {
+		return nil
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(privateKeyBlock.Bytes)
+
+	if _, ok := privateKey.(*rsa.PrivateKey); err != nil || !ok {
+		return errors.New("must be a valid rsa private key")
+	}
+
+	return nil
+}
+
+// Violations are keyed by the environment variable name of the field, so every invalid setting is reported at once.
+func (cfg *Config) Validate() error {
+	err := validation.ValidateStruct(cfg,
+		validation.Field(&cfg.AppEnv, validation.Required, validation.In("development", "staging", "production")),
+		validation.Field(&cfg.Port, validation.Required, is.Port),
+		validation.Field(&cfg.TimeZone, validation.Required, validation.By(TimeZoneValidation)),
+		validation.Field(&cfg.LogLevel, validation.By(LogLevelValidation)),
+		validation.Field(&cfg.LogFormat, validation.Required, validation.In("json", "text")),
+		validation.Field(&cfg.LogOutput, validation.Required, validation.In("stdout", "file", "both")),
+		validation.Field(&cfg.LogDirectory, validation.When(cfg.LogOutput != "stdout", validation.Required)),
+		validation.Field(&cfg.LogRotationTime, validation.Min(1)),
+		validation.Field(&cfg.LogRotationSize, validation.Min(0)),
+		validation.Field(&cfg.LogRotationCount, validation.Min(0)),
+		validation.Field(&cfg.LogMaxAge, validation.Min(0)),
+		validation.Field(&cfg.LogRedactStyle, validation.Required, validation.In("full", "partial", "hash")),
+		validation.Field(&cfg.LogBodyMaxSize, validation.Min(0)),
+		validation.Field(&cfg.EchoLogLevel, validation.Required, validation.In("debug", "info", "warn", "error", "off")),
+		validation.Field(&cfg.HSTSMaxAge, validation.Min(0)),
+		validation.Field(&cfg.TrustedProxies, validation.By(CIDRsValidation)),
+		validation.Field(&cfg.ClientIPHeader, validation.When(len(cfg.TrustedProxies) > 0, validation.Required, validation.In("X-Forwarded-For", "X-Real-IP"))),
+		validation.Field(&cfg.IPAllowList, validation.By(CIDRsValidation)),
+		validation.Field(&cfg.IPDenyList, validation.By(CIDRsValidation)),
+		validation.Field(&cfg.IPRouteAllowList, validation.By(IPRulesValidation)),
+		validation.Field(&cfg.IPRouteDenyList, validation.By(IPRulesValidation)),
+		validation.Field(&cfg.DatabaseConnection, validation.When(cfg.UseDatabase, validation.Required, validation.In("postgresql", "mysql"))),
+		validation.Field(&cfg.DatabaseHost, validation.When(cfg.UseDatabase, validation.Required)),
+		validation.Field(&cfg.DatabasePort, validation.When(cfg.UseDatabase, validation.Required, is.Port)),
+		validation.Field(&cfg.DatabaseName, validation.When(cfg.UseDatabase, validation.Required)),
+		validation.Field(&cfg.RedisHost, validation.When(cfg.UseRedis, validation.Required)),
+		validation.Field(&cfg.RedisPort, validation.When(cfg.UseRedis, validation.Required, is.Port)),
+		validation.Field(&cfg.SignatureName, validation.When(cfg.UseSignature, validation.Required)),
+		validation.Field(&cfg.SignatureValidationName, validation.When(cfg.UseSignature, validation.Required)),
+		validation.Field(&cfg.SignatureTransactionIDName, validation.When(cfg.UseSignature, validation.Required)),
+		validation.Field(&cfg.SignatureReplayTTL, validation.When(cfg.UseSignature, validation.Min(1)), validation.When(cfg.UseSignature && cfg.SignatureTimestamp != "disabled", validation.Min(cfg.SignatureClockSkew*2).Error("must be at least twice SIGNATURE_CLOCK_SKEW"))),
+		validation.Field(&cfg.SignatureReplayCacheSize, validation.When(cfg.UseSignature && !cfg.UseRedis, validation.Min(1))),
+		validation.Field(&cfg.SignatureTimestamp, validation.Required, validation.In("disabled", "optional", "required")),
+		validation.Field(&cfg.SignatureClockSkew, validation.Min(1)),
+		validation.Field(&cfg.SignatureMode, validation.Required, validation.In("hmac", "rsa-pss", "ed25519")),
+		validation.Field(&cfg.SignatureOAEPHash, validation.Required, validation.In("sha1", "sha256")),
+		validation.Field(&cfg.SignatureHMACHash, validation.When(cfg.SignatureMode == "hmac", validation.Required, validation.In("sha256", "sha384", "sha512"))),
+		validation.Field(&cfg.SignaturePublicKeyFiles, validation.When(cfg.UseSignature && cfg.SignatureMode != "hmac", validation.Required)),
+		validation.Field(&cfg.SignatureKeyIDName, validation.When(cfg.UseSignature && cfg.SignatureMode != "hmac", validation.Required)),
+		validation.Field(&cfg.SignatureVersionName, validation.When(cfg.UseSignature, validation.Required)),
+		validation.Field(&cfg.SignatureVersions, validation.When(cfg.UseSignature, validation.Required), validation.Each(validation.In("1", "2"))),
+		validation.Field(&cfg.UseResponseSignature, validation.When(cfg.UseResponseSignature && !cfg.UseSignature, validation.By(RequiresValidation("USE_SIGNATURE")))),
+		validation.Field(&cfg.ResponseSignatureName, validation.When(cfg.UseResponseSignature, validation.Required)),
+		validation.Field(&cfg.ResponseSignatureTimestampName, validation.When(cfg.UseResponseSignature, validation.Required)),
+		validation.Field(&cfg.RSAOAEPKey, validation.When(cfg.UseSignature, validation.Required), validation.By(RSAPrivateKeyValidation)),
+		validation.Field(&cfg.SecretKey, validation.When(cfg.UseSignature && cfg.SignatureMode == "hmac", validation.Required)),
+		validation.Field(&cfg.ServiceKeyStore, validation.Required, validation.In("config", "database"), validation.When(cfg.ServiceKeyStore == "database" && !cfg.UseDatabase, validation.By(RequiresValidation("USE_DATABASE")))),
+		validation.Field(&cfg.ServiceKey, validation.When(cfg.ServiceKeyStore == "config", validation.Required)),
+		validation.Field(&cfg.ServiceKeyPreviousExpiresAt, validation.Date(time.RFC3339)),
+		validation.Field(&cfg.ServiceKeyCacheTTL, validation.Min(0)),
+		validation.Field(&cfg.JWTAlgorithms, validation.When(cfg.UseJWT, validation.Required), validation.Each(validation.In("HS256", "RS256", "ES256"))),
+		validation.Field(&cfg.JWTSecret, validation.When(cfg.UseJWT && cfg.JWTJWKSFile == "" && len(cfg.JWTPublicKeyFiles) == 0, validation.Required)),
+		validation.Field(&cfg.JWTLeeway, validation.Min(0)),
+		validation.Field(&cfg.JWTScopeClaim, validation.When(cfg.UseJWT, validation.Required)),
+		validation.Field(&cfg.JWTRoleClaim, validation.When(cfg.UseJWT, validation.Required)),
+		validation.Field(&cfg.PermissionStore, validation.Required, validation.In("config", "database"), validation.When(cfg.PermissionStore == "database" && !cfg.UseDatabase, validation.By(RequiresValidation("USE_DATABASE")))),
+		validation.Field(&cfg.PermissionRoles, validation.By(PermissionRulesValidation)),
+		validation.Field(&cfg.PermissionRoutes, validation.By(PermissionRulesValidation)),
+		validation.Field(&cfg.PermissionCacheTTL, validation.Min(0)),
+		validation.Field(&cfg.RateLimitKey, validation.Required, validation.In("identity", "ip", "header")),
+		validation.Field(&cfg.RateLimitHeader, validation.When(cfg.RateLimitKey == "header", validation.Required)),
+		validation.Field(&cfg.RateLimitDefault, validation.By(RateLimitValidation)),
+		validation.Field(&cfg.RateLimitIP, validation.By(RateLimitValidation)),
+		validation.Field(&cfg.RateLimitRoutes, validation.By(RateLimitRoutesValidation)),
+		validation.Field(&cfg.IdempotencyStore, validation.Required, validation.In("memory", "redis", "database"), validation.When(cfg.IdempotencyStore == "redis" && !cfg.UseRedis, validation.By(RequiresValidation("USE_REDIS"))), validation.When(cfg.IdempotencyStore == "database" && !cfg.UseDatabase, validation.By(RequiresValidation("USE_DATABASE")))),
+		validation.Field(&cfg.IdempotencyTTL, validation.Min(1)),
+		validation.Field(&cfg.IdempotencyLockTimeout, validation.Min(1)),
+		validation.Field(&cfg.FieldEncryptionKeys, validation.When(cfg.UseFieldEncryption, validation.Required), validation.By(EncryptionKeysValidation)),
+		validation.Field(&cfg.FieldEncryptionKeyID, validation.When(cfg.UseFieldEncryption, validation.Required, validation.By(EncryptionKeyIDValidation(cfg.FieldEncryptionKeys)))),
+		validation.Field(&cfg.FieldBlindIndexKey, validation.When(cfg.UseFieldEncryption, validation.Required), validation.By(BlindIndexKeyValidation)),
+		validation.Field(&cfg.DefaultTimeout, validation.Min(1)),
+		validation.Field(&cfg.ShutdownTimeout, validation.Min(1)),
+		validation.Field(&cfg.ConfigWatchInterval, validation.Min(0)),
+		validation.Field(&cfg.CurrencyURL, validation.When(cfg.HealthCurrencyCheck != "disabled", validation.Required), is.URL),
+		validation.Field(&cfg.HealthCurrencyCheck, validation.In("disabled", "optional", "required")),
+	)
+
+	var fieldErrors validation.Errors
+
+	if !errors.As(err, &fieldErrors) {
+		return err
+	}
+
+	result := validation.Errors{}
+
+	for field, fieldError := range fieldErrors {
+		result[environmentName(field)] = fieldError
+	}
+
+	return result
+}
+
+func environmentName(field string) string {
+	structField, ok := reflect.TypeOf(Config{}).FieldByName(field)
+
+	if !ok {
+		return field
+	}
+
+	name, _, _ := strings.Cut(structField.Tag.Get("env"), ",")
+
+	if name == "" {
+		return field
+	}
+
+	return name
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/MrAndreID/goechoms/applications"
 	"github.com/MrAndreID/goechoms/applications/routes"
 	"github.com/MrAndreID/goechoms/configs"
@@ -19,7 +21,7 @@ func main() {
 			"error": err.Error(),
 		}).Error("failed to initiate configuration")
 
-		return
+		os.Exit(applications.ExitCodeServerError)
 	}
 
 	app, err := applications.New(cfg)
@@ -30,17 +32,20 @@ func main() {
 			"error": err.Error(),
 		}).Error("failed to initiate application")
 
-		return
+		os.Exit(applications.ExitCodeServerError)
 	}
 
 	e := routes.New(cfg, app)
 
-	if app.Start(cfg, e); err != nil {
+	exitCode, err := app.Start(cfg, e)
+
+	if err != nil {
 		logrus.WithFields(logrus.Fields{
-			"tag":   tag + "03",
-			"error": err.Error(),
+			"tag":      tag + "03",
+			"exitCode": exitCode,
+			"error":    err.Error(),
 		}).Error("failed to run application")
-
-		return
 	}
+
+	os.Exit(exitCode)
 }
@@ -0,0 +1,156 @@
+package signatures
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+)
+
+const (
+	ModeHMAC    string = "hmac"
+	ModeRSAPSS  string = "rsa-pss"
+	ModeEd25519 string = "ed25519"
+)
+
+var (
+	ErrInvalidSignature = errors.New("Invalid Signature")
+	ErrKeyNotFound      = errors.New("Key Not Found")
+)
+
+// Signatures are encoded in standard base64 whatever the algorithm.
+type Algorithm interface {
+	Sign(stringToSign string) (string, error)
+	Verify(stringToSign string, signature string) error
+}
+
+type HMAC struct {
+	Hash      crypto.Hash
+	SecretKey []byte
+}
+
+func (algorithm *HMAC) Sign(stringToSign string) (string, error) {
+	mac := hmac.New(algorithm.Hash.New, algorithm.SecretKey)
+
+	mac.Write([]byte(stringToSign))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (algorithm *HMAC) Verify(stringToSign string, signature string) error {
+	expected, _ := algorithm.Sign(stringToSign)
+
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+type RSAPSS struct {
+	Hash       crypto.Hash
+	PrivateKey *rsa.PrivateKey
+	PublicKey  *rsa.PublicKey
+}
+
+func (algorithm *RSAPSS) digest(stringToSign string) []byte {
+	hash := algorithm.Hash.New()
+
+	hash.Write([]byte(stringToSign))
+
+	return hash.Sum(nil)
+}
+
+func (algorithm *RSAPSS) Sign(stringToSign string) (string, error) {
+	if algorithm.PrivateKey == nil {
+		return "", ErrKeyNotFound
+	}
+
+	signature, err := rsa.SignPSS(rand.Reader, algorithm.PrivateKey, algorithm.Hash, algorithm.digest(stringToSign), &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+func (algorithm *RSAPSS) Verify(stringToSign string, signature string) error {
+	if algorithm.PublicKey == nil {
+		return ErrKeyNotFound
+	}
+
+	decodedSignature, err := base64.StdEncoding.DecodeString(signature)
+
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	if err := rsa.VerifyPSS(algorithm.PublicKey, algorithm.Hash, algorithm.digest(stringToSign), decodedSignature, &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthAuto,
+	}); err != nil {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+type Ed25519 struct {
+	PrivateKey ed25519.PrivateKey
+	PublicKey  ed25519.PublicKey
+}
+
+func (algorithm *Ed25519) Sign(stringToSign string) (string, error) {
+	if algorithm.PrivateKey == nil {
+		return "", ErrKeyNotFound
+	}
+
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(algorithm.PrivateKey, []byte(stringToSign))), nil
+}
+
+func (algorithm *Ed25519) Verify(stringToSign string, signature string) error {
+	if algorithm.PublicKey == nil {
+		return ErrKeyNotFound
+	}
+
+	decodedSignature, err := base64.StdEncoding.DecodeString(signature)
+
+	if err != nil || !ed25519.Verify(algorithm.PublicKey, []byte(stringToSign), decodedSignature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// The key is the secret key ([]byte) for hmac, and a private key for signing or a public key for verifying otherwise.
+func NewAlgorithm(mode string, hash crypto.Hash, key interface{}) (Algorithm, error) {
+	switch mode {
+	case ModeHMAC:
+		if secretKey, ok := key.([]byte); ok {
+			return &HMAC{Hash: hash, SecretKey: secretKey}, nil
+		}
+	case ModeRSAPSS:
+		switch rsaKey := key.(type) {
+		case *rsa.PrivateKey:
+			return &RSAPSS{Hash: hash, PrivateKey: rsaKey, PublicKey: &rsaKey.PublicKey}, nil
+		case *rsa.PublicKey:
+			return &RSAPSS{Hash: hash, PublicKey: rsaKey}, nil
+		}
+	case ModeEd25519:
+		switch ed25519Key := key.(type) {
+		case ed25519.PrivateKey:
+			return &Ed25519{PrivateKey: ed25519Key, PublicKey: ed25519Key.Public().(ed25519.PublicKey)}, nil
+		case ed25519.PublicKey:
+			return &Ed25519{PublicKey: ed25519Key}, nil
+		}
+	default:
+		return nil, errors.New("Signature Mode Not Found")
+	}
+
+	return nil, errors.New("Key Does Not Match Signature Mode")
+}
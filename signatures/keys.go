@@ -0,0 +1,122 @@
+package signatures
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
+
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+var hashes = map[string]crypto.Hash{
+	"sha1":   crypto.SHA1,
+	"sha256": crypto.SHA256,
+	"sha384": crypto.SHA384,
+	"sha512": crypto.SHA512,
+}
+
+func ParseHash(name string) (crypto.Hash, error) {
+	hash, ok := hashes[strings.ToLower(name)]
+
+	if !ok {
+		return 0, errors.New("Hash Not Found")
+	}
+
+	return hash, nil
+}
+
+// Both PKCS#1 ("RSA PRIVATE KEY") and PKCS#8 ("PRIVATE KEY") blocks are accepted.
+func ParsePrivateKey(content []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(content)
+
+	if block == nil {
+		return nil, errors.New("Invalid PEM Block")
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+
+	if !ok {
+		return nil, errors.New("Private Key Type Not Found")
+	}
+
+	return signer, nil
+}
+
+func ParseRSAPrivateKey(content []byte) (*rsa.PrivateKey, error) {
+	key, err := ParsePrivateKey(content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rsaPrivateKey, ok := key.(*rsa.PrivateKey)
+
+	if !ok {
+		return nil, errors.New("Private Key Is Not RSA")
+	}
+
+	return rsaPrivateKey, nil
+}
+
+// A private key is accepted as well, in which case its public part is used.
+func ParsePublicKey(content []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(content)
+
+	if block == nil {
+		return nil, errors.New("Invalid PEM Block")
+	}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		certificate, err := x509.ParseCertificate(block.Bytes)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return certificate.PublicKey, nil
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "RSA PRIVATE KEY", "PRIVATE KEY":
+		privateKey, err := ParsePrivateKey(content)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return privateKey.Public(), nil
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func ParseRSAPublicKey(content []byte) (*rsa.PublicKey, error) {
+	key, err := ParsePublicKey(content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rsaPublicKey, ok := key.(*rsa.PublicKey)
+
+	if !ok {
+		return nil, errors.New("Public Key Is Not RSA")
+	}
+
+	return rsaPublicKey, nil
+}
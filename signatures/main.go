@@ -0,0 +1,292 @@
+package signatures
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	Version1 string = "1"
+	Version2 string = "2"
+)
+
+var ErrVersionNotFound = errors.New("Signature Version Not Found")
+
+type Signer struct {
+	Version           string
+	PublicKey         *rsa.PublicKey
+	OAEPHash          crypto.Hash
+	Algorithm         Algorithm
+	KeyID             string
+	SignatureName     string
+	TransactionIDName string
+	KeyIDName         string
+	VersionName       string
+	TransactionID     func() string
+}
+
+type Headers struct {
+	Signature     string
+	TransactionID string
+	KeyID         string
+	Version       string
+}
+
+// The signer uses version 2 of the default scheme (RSA-OAEP with SHA-1 and HMAC-SHA512), other algorithms are set on the returned signer.
+func NewSigner(publicKey []byte, secretKey string, signatureName string, transactionIDName string) (*Signer, error) {
+	rsaPublicKey, err := ParseRSAPublicKey(publicKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{
+		Version:           Version2,
+		PublicKey:         rsaPublicKey,
+		OAEPHash:          crypto.SHA1,
+		Algorithm:         &HMAC{Hash: crypto.SHA512, SecretKey: []byte(secretKey)},
+		SignatureName:     signatureName,
+		TransactionIDName: transactionIDName,
+		VersionName:       "X-Signature-Version",
+		TransactionID:     NewTransactionID,
+	}, nil
+}
+
+// The transaction ID embeds the unix time it was created at, so it is accepted whatever SIGNATURE_TIMESTAMP is set to.
+func NewTransactionID() string {
+	return uuid.NewString() + ":" + strconv.FormatInt(time.Now().Unix(), 10)
+}
+
+// Version 1 signs "?query|body|txid", where the body is replaced by "{}" when it is empty, for GET requests and for multipart requests.
+func legacyStringToSign(method string, rawQuery string, contentType string, body []byte, transactionID string) string {
+	var query, bodyString string = "", string(body)
+
+	if rawQuery != "" {
+		query = "?" + rawQuery
+	}
+
+	if bodyString == "" || method == http.MethodGet || contentType == "multipart/form-data" {
+		bodyString = "{}"
+	}
+
+	return query + "|" + bodyString + "|" + transactionID
+}
+
+// Version 2 signs the version, method, path, query, body digest and transaction ID, each on its own line.
+func StringToSign(version string, method string, path string, rawQuery string, contentType string, body []byte, transactionID string) (string, error) {
+	switch version {
+	case Version1:
+		return legacyStringToSign(method, rawQuery, contentType, body, transactionID), nil
+	case Version2:
+		digest, err := BodyDigest(contentType, body)
+
+		if err != nil {
+			return "", err
+		}
+
+		return strings.Join([]string{"v" + version, strings.ToUpper(method), CanonicalPath(path), rawQuery, digest, transactionID}, "\n"), nil
+	}
+
+	return "", ErrVersionNotFound
+}
+
+// The trailing slash is removed like the server router does, so "/api/v1/user/" is signed as "/api/v1/user".
+func CanonicalPath(path string) string {
+	if len(path) > 1 && strings.HasSuffix(path, "/") {
+		return path[:len(path)-1]
+	}
+
+	return path
+}
+
+// Form bodies are digested in a canonical form (fields sorted by name, file parts replaced by the SHA-256 of their content), other bodies as they are.
+func BodyDigest(contentType string, body []byte) (string, error) {
+	var mediaType string
+
+	if contentType != "" {
+		parsedMediaType, params, err := mime.ParseMediaType(contentType)
+
+		if err != nil {
+			return "", err
+		}
+
+		mediaType = parsedMediaType
+
+		switch mediaType {
+		case "application/x-www-form-urlencoded":
+			values, err := url.ParseQuery(string(body))
+
+			if err != nil {
+				return "", err
+			}
+
+			body = []byte(values.Encode())
+		case "multipart/form-data":
+			values, err := multipartValues(body, params["boundary"])
+
+			if err != nil {
+				return "", err
+			}
+
+			body = []byte(values.Encode())
+		}
+	}
+
+	sum := sha256.Sum256(body)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func multipartValues(body []byte, boundary string) (url.Values, error) {
+	var values url.Values = url.Values{}
+
+	if boundary == "" {
+		return nil, errors.New("Multipart Boundary Not Found")
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+
+	for {
+		part, err := reader.NextPart()
+
+		if errors.Is(err, io.EOF) {
+			return values, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := io.ReadAll(part)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if part.FileName() != "" {
+			sum := sha256.Sum256(content)
+
+			values.Add(part.FormName(), "sha256:"+hex.EncodeToString(sum[:]))
+		} else {
+			values.Add(part.FormName(), string(content))
+		}
+	}
+}
+
+func EncryptTransactionID(publicKey *rsa.PublicKey, hash crypto.Hash, transactionID string) (string, error) {
+	encryptedData, err := rsa.EncryptOAEP(hash.New(), rand.Reader, publicKey, []byte(transactionID), nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(encryptedData), nil
+}
+
+func DecryptTransactionID(privateKey *rsa.PrivateKey, hash crypto.Hash, encryptedTransactionID string) (string, error) {
+	decodedData, err := base64.StdEncoding.DecodeString(encryptedTransactionID)
+
+	if err != nil {
+		return "", err
+	}
+
+	decryptedData, err := rsa.DecryptOAEP(hash.New(), rand.Reader, privateKey, decodedData, nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(decryptedData), nil
+}
+
+func (signer *Signer) Headers(method string, path string, rawQuery string, contentType string, body []byte) (*Headers, error) {
+	transactionID := signer.TransactionID()
+
+	encryptedTransactionID, err := EncryptTransactionID(signer.PublicKey, signer.OAEPHash, transactionID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stringToSign, err := StringToSign(signer.Version, method, path, rawQuery, contentType, body, transactionID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := signer.Algorithm.Sign(stringToSign)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Headers{
+		Signature:     signature,
+		TransactionID: encryptedTransactionID,
+		KeyID:         signer.KeyID,
+		Version:       signer.Version,
+	}, nil
+}
+
+// The body is read and put back, so the request can still be sent afterwards.
+func (signer *Signer) SignRequest(request *http.Request) error {
+	var body []byte
+
+	if request.Body != nil {
+		bodyBytes, err := io.ReadAll(request.Body)
+
+		if err != nil {
+			return err
+		}
+
+		request.Body.Close()
+
+		body = bodyBytes
+
+		request.Body = io.NopCloser(bytes.NewReader(body))
+		request.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	headers, err := signer.Headers(request.Method, request.URL.EscapedPath(), request.URL.RawQuery, request.Header.Get("Content-Type"), body)
+
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set(signer.SignatureName, headers.Signature)
+	request.Header.Set(signer.TransactionIDName, headers.TransactionID)
+
+	if headers.KeyID != "" && signer.KeyIDName != "" {
+		request.Header.Set(signer.KeyIDName, headers.KeyID)
+	}
+
+	if headers.Version != Version1 && signer.VersionName != "" {
+		request.Header.Set(signer.VersionName, headers.Version)
+	}
+
+	return nil
+}
+
+// Usage : restyClient.SetPreRequestHook(signer.RestyPreRequestHook)
+func (signer *Signer) RestyPreRequestHook(client *resty.Client, request *http.Request) error {
+	return signer.SignRequest(request)
+}
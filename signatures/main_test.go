@@ -0,0 +1,97 @@
+package signatures
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func TestSignRequestWithTrailingSlash(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	algorithm := &HMAC{Hash: crypto.SHA512, SecretKey: []byte("secret")}
+
+	signer := &Signer{
+		Version:           Version2,
+		PublicKey:         &privateKey.PublicKey,
+		OAEPHash:          crypto.SHA1,
+		Algorithm:         algorithm,
+		SignatureName:     "X-Signature",
+		TransactionIDName: "X-Transaction-Id",
+		TransactionID:     NewTransactionID,
+	}
+
+	// The server verifies the path after the router has removed the trailing slash.
+	e := echo.New()
+
+	e.Pre(middleware.RemoveTrailingSlash())
+
+	e.POST("/api/v1/user", func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+
+		if err != nil {
+			return err
+		}
+
+		transactionID, err := DecryptTransactionID(privateKey, crypto.SHA1, c.Request().Header.Get("X-Transaction-Id"))
+
+		if err != nil {
+			return err
+		}
+
+		stringToSign, err := StringToSign(Version2, c.Request().Method, c.Request().URL.EscapedPath(), c.Request().URL.RawQuery, c.Request().Header.Get("Content-Type"), body, transactionID)
+
+		if err != nil {
+			return err
+		}
+
+		if err := algorithm.Verify(stringToSign, c.Request().Header.Get("X-Signature")); err != nil {
+			return c.NoContent(http.StatusUnauthorized)
+		}
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, path := range []string{"/api/v1/user", "/api/v1/user/"} {
+		request := httptest.NewRequest(http.MethodPost, path+"?page=1", bytes.NewBufferString(`{"name":"User"}`))
+
+		request.Header.Set("Content-Type", "application/json")
+
+		if err := signer.SignRequest(request); err != nil {
+			t.Fatal(err)
+		}
+
+		recorder := httptest.NewRecorder()
+
+		e.ServeHTTP(recorder, request)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected %s to be verified, got %d", path, recorder.Code)
+		}
+	}
+}
+
+func TestCanonicalPath(t *testing.T) {
+	for path, expected := range map[string]string{
+		"":              "",
+		"/":             "/",
+		"/api/v1/user":  "/api/v1/user",
+		"/api/v1/user/": "/api/v1/user",
+	} {
+		if actual := CanonicalPath(path); actual != expected {
+			t.Fatalf("expected %q for %q, got %q", expected, path, actual)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package signatures
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// A response signature covers the status, the timestamp and the hex encoded SHA-256 of the body, each on its own line.
+func ResponseStringToSign(status int, timestamp string, body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return strings.Join([]string{strconv.Itoa(status), timestamp, hex.EncodeToString(sum[:])}, "\n")
+}
+
+func SignResponse(algorithm Algorithm, status int, timestamp string, body []byte) (string, error) {
+	return algorithm.Sign(ResponseStringToSign(status, timestamp, body))
+}
+
+func VerifyResponse(algorithm Algorithm, status int, timestamp string, body []byte, signature string) error {
+	return algorithm.Verify(ResponseStringToSign(status, timestamp, body), signature)
+}
+
+// The body is read and returned, since it can not be read from the response again. Checking the age of the timestamp is left to the caller.
+func VerifyHTTPResponse(algorithm Algorithm, response *http.Response, signatureName string, timestampName string) ([]byte, error) {
+	body, err := io.ReadAll(response.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response.Body.Close()
+
+	if err := VerifyResponse(algorithm, response.StatusCode, response.Header.Get(timestampName), body, response.Header.Get(signatureName)); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
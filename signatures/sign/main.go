@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/MrAndreID/goechoms/configs"
+	"github.com/MrAndreID/goechoms/signatures"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	var (
+		tag               string  = "Signatures.Sign.Main."
+		method            *string = flag.String("method", http.MethodGet, "HTTP method of the request")
+		url               *string = flag.String("url", "", "URL of the request, including the query string")
+		body              *string = flag.String("body", "", "Body of the request, or @path to read it from a file")
+		contentType       *string = flag.String("content-type", "application/json", "Content type of the body")
+		transactionID     *string = flag.String("transaction-id", "", "Transaction ID, generated when empty")
+		publicKeyFile     *string = flag.String("public-key", "", "Path to the RSA public key, defaults to the public part of RSA_OAEP_KEY")
+		secretKey         *string = flag.String("secret-key", "", "Secret key, defaults to SECRET_KEY")
+		signatureName     *string = flag.String("signature-name", "", "Signature header, defaults to SIGNATURE_NAME")
+		transactionIDName *string = flag.String("transaction-id-name", "", "Transaction ID header, defaults to SIGNATURE_TRANSACTION_ID_NAME")
+		mode              *string = flag.String("mode", "", "Signature mode (hmac, rsa-pss or ed25519), defaults to SIGNATURE_MODE")
+		oaepHash          *string = flag.String("oaep-hash", "", "Hash of RSA-OAEP, defaults to SIGNATURE_OAEP_HASH")
+		hmacHash          *string = flag.String("hmac-hash", "", "Hash of HMAC, defaults to SIGNATURE_HMAC_HASH")
+		privateKeyFile    *string = flag.String("private-key", "", "Path to the private key of the client, required by the rsa-pss and ed25519 modes")
+		keyID             *string = flag.String("key-id", "", "Key ID of the private key of the client")
+		keyIDName         *string = flag.String("key-id-name", "", "Key ID header, defaults to SIGNATURE_KEY_ID_NAME")
+		version           *string = flag.String("signature-version", signatures.Version2, "Version of the signature scheme")
+		versionName       *string = flag.String("signature-version-name", "", "Signature version header, defaults to SIGNATURE_VERSION_NAME")
+		publicKey         []byte
+		err               error
+	)
+
+	flag.Parse()
+
+	logrus.SetOutput(os.Stderr)
+
+	if *url == "" {
+		logrus.WithFields(logrus.Fields{
+			"tag": tag + "01",
+		}).Error("url is required")
+
+		os.Exit(1)
+	}
+
+	if *publicKeyFile == "" || *signatureName == "" || *transactionIDName == "" {
+		cfg, err := configs.Parse()
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "02",
+				"error": err.Error(),
+			}).Error("failed to load configuration")
+
+			os.Exit(1)
+		}
+
+		publicKey = []byte(cfg.RSAOAEPKey)
+
+		for flagValue, value := range map[*string]string{
+			secretKey:         cfg.SecretKey,
+			signatureName:     cfg.SignatureName,
+			transactionIDName: cfg.SignatureTransactionIDName,
+			mode:              cfg.SignatureMode,
+			oaepHash:          cfg.SignatureOAEPHash,
+			hmacHash:          cfg.SignatureHMACHash,
+			keyIDName:         cfg.SignatureKeyIDName,
+			versionName:       cfg.SignatureVersionName,
+		} {
+			if *flagValue == "" {
+				*flagValue = value
+			}
+		}
+	}
+
+	for flagValue, value := range map[*string]string{
+		mode:        signatures.ModeHMAC,
+		oaepHash:    "sha1",
+		hmacHash:    "sha512",
+		keyIDName:   "X-Signature-Key-ID",
+		versionName: "X-Signature-Version",
+	} {
+		if *flagValue == "" {
+			*flagValue = value
+		}
+	}
+
+	if *publicKeyFile != "" {
+		if publicKey, err = os.ReadFile(*publicKeyFile); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "03",
+				"path":  *publicKeyFile,
+				"error": err.Error(),
+			}).Error("failed to read public key file")
+
+			os.Exit(1)
+		}
+	}
+
+	if strings.HasPrefix(*body, "@") {
+		content, err := os.ReadFile(strings.TrimPrefix(*body, "@"))
+
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"tag":   tag + "04",
+				"path":  strings.TrimPrefix(*body, "@"),
+				"error": err.Error(),
+			}).Error("failed to read body file")
+
+			os.Exit(1)
+		}
+
+		*body = string(content)
+	}
+
+	signer, err := newSigner(publicKey, *secretKey, *mode, *oaepHash, *hmacHash, *privateKeyFile)
+
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "05",
+			"error": err.Error(),
+		}).Error("failed to initiate signer")
+
+		os.Exit(1)
+	}
+
+	signer.SignatureName = *signatureName
+	signer.TransactionIDName = *transactionIDName
+	signer.KeyID = *keyID
+	signer.KeyIDName = *keyIDName
+	signer.Version = *version
+	signer.VersionName = *versionName
+
+	if *transactionID != "" {
+		signer.TransactionID = func() string {
+			return *transactionID
+		}
+	}
+
+	var requestBody io.Reader
+
+	if *body != "" {
+		requestBody = bytes.NewBufferString(*body)
+	}
+
+	request, err := http.NewRequest(strings.ToUpper(*method), *url, requestBody)
+
+	if err == nil {
+		if *body != "" {
+			request.Header.Set("Content-Type", *contentType)
+		}
+
+		err = signer.SignRequest(request)
+	}
+
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"tag":   tag + "06",
+			"error": err.Error(),
+		}).Error("failed to sign request")
+
+		os.Exit(1)
+	}
+
+	fmt.Println(signer.SignatureName + ": " + request.Header.Get(signer.SignatureName))
+	fmt.Println(signer.TransactionIDName + ": " + request.Header.Get(signer.TransactionIDName))
+
+	for _, name := range []string{signer.KeyIDName, signer.VersionName} {
+		if value := request.Header.Get(name); value != "" {
+			fmt.Println(name + ": " + value)
+		}
+	}
+
+	fmt.Println()
+
+	command := []string{"curl", "-X", request.Method, quote(*url)}
+
+	for _, name := range []string{"Content-Type", signer.SignatureName, signer.TransactionIDName, signer.KeyIDName, signer.VersionName} {
+		if value := request.Header.Get(name); value != "" {
+			command = append(command, "-H", quote(name+": "+value))
+		}
+	}
+
+	if *body != "" {
+		command = append(command, "--data-raw", quote(*body))
+	}
+
+	fmt.Println(strings.Join(command, " "))
+}
+
+func newSigner(publicKey []byte, secretKey string, mode string, oaepHashName string, hmacHashName string, privateKeyFile string) (*signatures.Signer, error) {
+	signer, err := signatures.NewSigner(publicKey, secretKey, "", "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if signer.OAEPHash, err = signatures.ParseHash(oaepHashName); err != nil {
+		return nil, err
+	}
+
+	if mode == signatures.ModeHMAC {
+		hmacHash, err := signatures.ParseHash(hmacHashName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		signer.Algorithm = &signatures.HMAC{Hash: hmacHash, SecretKey: []byte(secretKey)}
+
+		return signer, nil
+	}
+
+	content, err := os.ReadFile(privateKeyFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := signatures.ParsePrivateKey(content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	signer.Algorithm, err = signatures.NewAlgorithm(mode, crypto.SHA256, privateKey)
+
+	return signer, err
+}
+
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}